@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"pirate-wars/cmd/clock"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/entities"
+	"pirate-wars/cmd/ship"
+	"pirate-wars/cmd/spatial"
+	"pirate-wars/cmd/terrain"
+	"pirate-wars/cmd/town"
+	"pirate-wars/cmd/world"
+)
+
+// ChannelCost gold paid out for the picks, shovels and extra grog it takes to dig a channel
+const ChannelCost = 100
+
+// ChannelCrew hands it takes to dig a channel
+const ChannelCrew = 20
+
+// ChannelTicks ticks the landing party spends digging each tile of a channel
+const ChannelTicks = clock.TicksPerDay / 2
+
+// channelState the tile the landing party is digging a channel through, and the work left
+type channelState struct {
+	pos   common.Coordinates
+	ticks int
+}
+
+var ChannelData *channelState
+
+// searchedRuins ghost towns whose ruins the player has already turned over
+var searchedRuins = map[string]bool{}
+
+// focus whoever the view follows, the landing party while the player is ashore or else the ship
+func (gs *GameState) focus() entities.AvatarReadOnly {
+	if gs.player.IsAshore() {
+		return gs.player.GetParty()
+	}
+	return gs.player
+}
+
+// mapView the view of the map the player returns to, ashore or at sea
+func (gs *GameState) mapView() int {
+	if gs.player.IsAshore() {
+		return world.ViewTypeAshore
+	}
+	return world.ViewTypeMainMap
+}
+
+// isWalkable whether a landing party can cross c, peaks are too steep to climb
+func (gs *GameState) isWalkable(c common.Coordinates) bool {
+	tt := gs.world.GetPositionType(c)
+	return terrain.TypeLookup[tt].Passable && !terrain.TypeLookup[tt].RequiresBoat
+}
+
+// goAshore anchors the ship and lands a party on the coast alongside, returns false if there's
+// nowhere to land
+func (gs *GameState) goAshore() bool {
+	var landing *common.Coordinates
+	for _, c := range gs.world.GetAdjacentCoords(gs.player.GetPos()) {
+		tt := gs.world.GetPositionType(c)
+		if !gs.isWalkable(c) || tt == common.TerrainTypeTown || tt == common.TerrainTypeHideout {
+			continue
+		}
+		if landing == nil || tt == common.TerrainTypeBeach {
+			landing = &c
+		}
+	}
+	if landing == nil {
+		return false
+	}
+	gs.player.GetHelm().Moor()
+	gs.player.Land(*landing, gs.index)
+	ViewType = world.ViewTypeAshore
+	notify("Dropped anchor and went ashore")
+	return true
+}
+
+// embark the landing party rows back out to the ship
+func (gs *GameState) embark() {
+	party := gs.player.GetParty()
+	if ChannelData != nil {
+		notify("The party is busy digging")
+		return
+	}
+	if common.Distance(party.GetPos(), gs.player.GetPos()) > 1 {
+		notify("Make your way back to the ship to re-embark")
+		return
+	}
+	gs.player.Embark(gs.index)
+	ViewType = world.ViewTypeMainMap
+	notify("Back aboard, weighed anchor")
+}
+
+// walk moves the landing party to t, walking into the ship takes them back aboard
+func (gs *GameState) walk(t common.Coordinates) {
+	if common.CoordsMatch(t, gs.player.GetPos()) {
+		gs.embark()
+		return
+	}
+	if ChannelData != nil {
+		notify("The party is busy digging")
+		return
+	}
+	if gs.isWalkable(t) {
+		gs.player.GetParty().SetPos(t)
+	}
+}
+
+// step walks the landing party a tile in direction d from wherever it stands
+func (gs *GameState) step(d common.Coordinates) {
+	if t := common.AddDirection(gs.player.GetParty().GetPos(), d); common.Inbounds(t) {
+		gs.walk(t)
+	}
+}
+
+// dig the landing party digs where they stand and round about, for buried treasure or whatever
+// can be found in the ruins of a ghost town
+func (gs *GameState) dig() {
+	pos := gs.player.GetParty().GetPos()
+	for _, c := range append(gs.world.GetAdjacentCoords(pos), pos) {
+		if gold, ok := gs.treasures.Dig(c); ok {
+			gs.player.AddGold(gold)
+			gs.player.GetShip().ChangeMorale(ship.PlunderMorale)
+			notify(fmt.Sprintf("X marks the spot! Dug up %d gold", gold))
+			return
+		}
+	}
+	for _, item := range gs.index.QueryRadius(pos, 1, spatial.KindTown) {
+		t := item.(*town.Town)
+		if t.IsGhostTown() && !searchedRuins[t.GetID()] {
+			searchedRuins[t.GetID()] = true
+			notify(fmt.Sprintf("The party searched the ruins of %s", t.GetName()))
+			gs.findMap(fmt.Sprintf("the ruins of %s", t.GetName()))
+			return
+		}
+	}
+	notify("The party dug, but found nothing")
+}
+
+// digChannel the landing party sets to work turning the land they stand on into a channel, it has
+// to be on the water's edge and clear of towns
+func (gs *GameState) digChannel() {
+	pos := gs.player.GetParty().GetPos()
+	tt := gs.world.GetPositionType(pos)
+	if ChannelData != nil {
+		notify("The party is already digging")
+		return
+	}
+	if !gs.world.IsLand(pos) || !gs.world.IsAdjacentToWater(pos) {
+		notify("A channel has to be dug from the water's edge")
+		return
+	}
+	if tt == common.TerrainTypeTown || tt == common.TerrainTypeGhostTown || tt == common.TerrainTypeHideout {
+		notify("Can't dig a channel through a town")
+		return
+	}
+	if gs.player.GetGold() < ChannelCost || gs.player.GetShip().GetCrew() < ChannelCrew {
+		notify(fmt.Sprintf("A channel takes %d gold and %d crew to dig", ChannelCost, ChannelCrew))
+		return
+	}
+	gs.player.SpendGold(ChannelCost)
+	ChannelData = &channelState{pos: pos, ticks: ChannelTicks}
+	notify("The party sets to digging a channel")
+}
+
+// processChannel the party digs on, once done the sea floods in, the towns' heatmaps are brought
+// up to date and the traders plan their routes through the new channel
+func (gs *GameState) processChannel() {
+	if ChannelData == nil {
+		return
+	}
+	ChannelData.ticks--
+	if ChannelData.ticks > 0 {
+		return
+	}
+	c := ChannelData.pos
+	ChannelData = nil
+	gs.towns.DigChannel(c, gs.world)
+	gs.npcs.Replan()
+	notify(fmt.Sprintf("Channel dug at %v", c))
+	party := gs.player.GetParty()
+	if prev := party.GetPreviousPos(); gs.isWalkable(prev) {
+		party.SetPos(prev)
+		return
+	}
+	for _, n := range gs.world.GetAdjacentCoords(c) {
+		if gs.isWalkable(n) {
+			party.SetPos(n)
+			return
+		}
+	}
+	gs.player.Embark(gs.index)
+	ViewType = world.ViewTypeMainMap
+	notify("The party swam back to the ship")
+}
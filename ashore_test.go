@@ -0,0 +1,52 @@
+package main
+
+import (
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/player"
+	"pirate-wars/cmd/spatial"
+	"pirate-wars/cmd/world"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"go.uber.org/zap"
+)
+
+func TestWalkInland(t *testing.T) {
+	test.NewApp()
+	test.NewWindow(nil)
+	gs := &GameState{logger: zap.NewNop().Sugar(), index: spatial.New()}
+	gs.world = world.Init(gs.logger)
+	gs.player = player.Create(gs.world, gs.index)
+
+	// find a ship's berth with three walkable tiles running inland to the east of it
+	east := common.Coordinates{X: 1, Y: 0}
+	var berth *common.Coordinates
+	for x := 0; x < common.WorldCols-4 && berth == nil; x++ {
+		for y := 0; y < common.WorldRows && berth == nil; y++ {
+			c := common.Coordinates{X: x, Y: y}
+			if !gs.world.IsPassableByBoat(c) {
+				continue
+			}
+			inland := true
+			for i, p := 0, c; i < 3; i++ {
+				p = common.AddDirection(p, east)
+				inland = inland && gs.isWalkable(p)
+			}
+			if inland {
+				berth = &c
+			}
+		}
+	}
+	if berth == nil {
+		t.Skip("no stretch of coast to walk inland from")
+	}
+	gs.player.SetPos(*berth)
+	gs.player.Land(common.AddDirection(*berth, east), gs.index)
+	right, _ := navKeyMap.find("right")
+	right.exec(*gs)
+	right.exec(*gs)
+	want := common.Coordinates{X: berth.X + 3, Y: berth.Y}
+	if got := gs.player.GetParty().GetPos(); !common.CoordsMatch(got, want) {
+		t.Fatalf("the party should walk inland from where it stands to %v, got to %v", want, got)
+	}
+}
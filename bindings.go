@@ -0,0 +1,250 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/keys"
+	"pirate-wars/cmd/window"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"go.uber.org/zap"
+)
+
+// bindableKeyMap a key map players can rebind, known by its name in the key bindings file
+type bindableKeyMap struct {
+	name   string
+	keyMap *KeyMap
+}
+
+var bindableKeyMaps = []bindableKeyMap{
+	{name: "sailing", keyMap: &sailingKeyMap},
+	{name: "ashore", keyMap: &ashoreKeyMap},
+	{name: "minimap", keyMap: &miniMapKeyMap},
+	{name: "examine", keyMap: &examineKeyMap},
+}
+
+// defaultBindings the bindings the game ships with, before any are loaded from the file
+var defaultBindings = bindings()
+
+// bindingState the key map and action picked on the key bindings screen, and whether the next key
+// pressed is to be bound to it
+type bindingState struct {
+	keyMap    int
+	selected  int
+	capturing bool
+}
+
+var BindingData = &bindingState{}
+
+var bindingsPopup *widget.PopUp
+
+// actions the keys bound to each of the key map's actions, by action name
+func (km KeyMap) actions() map[string][]string {
+	actions := map[string][]string{}
+	for _, k := range km {
+		actions[k.action] = append([]string{}, k.key...)
+	}
+	return actions
+}
+
+// find the key map's item for the action
+func (km KeyMap) find(action string) (keyItem, bool) {
+	for _, k := range km {
+		if k.action == action {
+			return k, true
+		}
+	}
+	return keyItem{}, false
+}
+
+// keyRenames keys older bindings files name that fyne never reports, with the key it reports
+// instead, e.g. "/" for "?" as it ignores shift
+var keyRenames = map[string]string{"?": "/"}
+
+// migrate the saved bindings brought up to date: by action name, where files written before
+// actions had names kept them by their help text, and to keys fyne reports. Returns whether any
+// needed changing.
+func (km KeyMap) migrate(saved map[string][]string) (map[string][]string, bool) {
+	actions := map[string][]string{}
+	changed := false
+	for name, bound := range saved {
+		if _, ok := km.find(name); !ok {
+			for _, k := range km {
+				if k.help == name {
+					name, changed = k.action, true
+				}
+			}
+		}
+		keys := []string{}
+		for _, k := range bound {
+			if r, ok := keyRenames[k]; ok {
+				k, changed = r, true
+			}
+			keys = append(keys, k)
+		}
+		actions[name] = keys
+	}
+	return actions, changed
+}
+
+// bindings the keys bound to the actions of each of the bindable key maps
+func bindings() keys.Bindings {
+	b := keys.Bindings{}
+	for _, bk := range bindableKeyMaps {
+		b[bk.name] = bk.keyMap.actions()
+	}
+	return b
+}
+
+// bind rebinds the key map's actions to the keys given, leaving those not mentioned as they are.
+// If a key would be bound to more than one action the key map is left unchanged and the conflicts
+// are returned.
+func bind(km *KeyMap, actions map[string][]string) map[string][]string {
+	rebound := make(KeyMap, len(*km))
+	copy(rebound, *km)
+	for i, k := range rebound {
+		if bound, ok := actions[k.action]; ok && len(bound) > 0 {
+			rebound[i].key = bound
+		}
+	}
+	if c := keys.Conflicts(rebound.actions()); len(c) > 0 {
+		return c
+	}
+	*km = rebound
+	return nil
+}
+
+// loadBindings rebinds keys from the key bindings file, writing one out with the defaults if
+// there's none yet, or brought up to date if it's an old one. A key map with conflicting bindings
+// keeps its defaults.
+func loadBindings(logger *zap.SugaredLogger) {
+	b, err := keys.Read(common.KeysFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		writeBindings(logger)
+		return
+	}
+	if err != nil {
+		logger.Errorf("Failed to read key bindings: %v", err)
+		return
+	}
+	outdated := false
+	for _, bk := range bindableKeyMaps {
+		actions, changed := bk.keyMap.migrate(b[bk.name])
+		outdated = outdated || changed
+		if c := bind(bk.keyMap, actions); c != nil {
+			logger.Warnf("Conflicting %s key bindings %v, keeping the defaults", bk.name, c)
+			notify(fmt.Sprintf("Conflicting %s key bindings, keeping the defaults", bk.name))
+		}
+	}
+	if outdated {
+		writeBindings(logger)
+	}
+}
+
+func writeBindings(logger *zap.SugaredLogger) {
+	if err := keys.Write(common.KeysFile, bindings()); err != nil {
+		logger.Errorf("Failed to write key bindings: %v", err)
+		notify("Failed to save the key bindings")
+	}
+}
+
+// selectedBinding the key map and the action of it picked on the key bindings screen
+func selectedBinding() (bindableKeyMap, keyItem) {
+	BindingData.keyMap = (BindingData.keyMap + len(bindableKeyMaps)) % len(bindableKeyMaps)
+	bk := bindableKeyMaps[BindingData.keyMap]
+	BindingData.selected = (BindingData.selected + len(*bk.keyMap)) % len(*bk.keyMap)
+	return bk, (*bk.keyMap)[BindingData.selected]
+}
+
+func selectBindableKeyMap(d int) {
+	BindingData.keyMap += d
+	BindingData.selected = 0
+	selectedBinding()
+}
+
+func selectBinding(d int) {
+	BindingData.selected += d
+	selectedBinding()
+}
+
+// rebind binds key to the action picked in place of its current keys
+func (gs *GameState) rebind(key string) {
+	BindingData.capturing = false
+	bk, item := selectedBinding()
+	gs.applyBinding(bk, item, []string{key})
+}
+
+// resetBinding restores the default keys of the action picked
+func (gs *GameState) resetBinding() {
+	bk, item := selectedBinding()
+	gs.applyBinding(bk, item, defaultBindings[bk.name][item.action])
+}
+
+// applyBinding binds the keys to the item of the key map, unless they're bound to another of its
+// actions, and saves the bindings
+func (gs *GameState) applyBinding(bk bindableKeyMap, item keyItem, bound []string) {
+	if c := bind(bk.keyMap, map[string][]string{item.action: bound}); c != nil {
+		for k, actions := range c {
+			for _, a := range actions {
+				if other, ok := bk.keyMap.find(a); ok && a != item.action {
+					notify(fmt.Sprintf("%s is already bound to %s", keyName(k), other.help))
+					return
+				}
+			}
+		}
+		return
+	}
+	writeBindings(gs.logger)
+	notify(fmt.Sprintf("Bound %s to %s", item.help, strings.Join(keyLabels(bound), ", ")))
+}
+
+func keyLabels(bound []string) []string {
+	names := []string{}
+	for _, k := range bound {
+		names = append(names, keyName(k))
+	}
+	return names
+}
+
+func bindingsContent() *fyne.Container {
+	bk, selected := selectedBinding()
+	lines := []string{}
+	for _, k := range *bk.keyMap {
+		marker := "  "
+		if k.action == selected.action {
+			marker = "> "
+		}
+		lines = append(lines, fmt.Sprintf("%s%s: %s", marker, k.help, strings.Join(keyLabels(k.key), ", ")))
+	}
+	prompt := "Enter to rebind, Backspace to restore the default"
+	if BindingData.capturing {
+		prompt = fmt.Sprintf("Press the key to bind to %s", selected.help)
+	}
+	return container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("Key bindings: %s (%d/%d)", bk.name, BindingData.keyMap+1, len(bindableKeyMaps))),
+		widget.NewLabel(strings.Join(lines, "\n")),
+		widget.NewLabel(prompt),
+	)
+}
+
+func showBindingsPopup(w fyne.Window) {
+	hideBindingsPopup()
+	bindingsPopup = widget.NewModalPopUp(bindingsContent(), w.Canvas())
+	bindingsPopup.Resize(fyne.NewSize(float32(window.MiniMapArea.Width), float32(window.MiniMapArea.Height)))
+	bindingsPopup.Move(
+		fyne.NewPos(float32(window.Window.Width-window.MiniMapArea.Width)/2,
+			float32(window.Window.Height-window.MiniMapArea.Height)/2),
+	)
+	bindingsPopup.Show()
+}
+
+func hideBindingsPopup() {
+	if bindingsPopup != nil {
+		bindingsPopup.Hide()
+	}
+}
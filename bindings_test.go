@@ -0,0 +1,71 @@
+package main
+
+import (
+	"pirate-wars/cmd/keys"
+	"pirate-wars/cmd/world"
+	"strings"
+	"testing"
+)
+
+func TestDefaultBindings(t *testing.T) {
+	for _, bk := range bindableKeyMaps {
+		if c := keys.Conflicts(bk.keyMap.actions()); len(c) > 0 {
+			t.Fatalf("default %s key bindings conflict: %v", bk.name, c)
+		}
+		named := map[string]bool{}
+		for _, k := range *bk.keyMap {
+			if k.action == "" || named[k.action] {
+				t.Fatalf("every %s action should have a name of its own, %q doesn't", bk.name, k.help)
+			}
+			named[k.action] = true
+		}
+	}
+}
+
+func TestBindConflict(t *testing.T) {
+	km := append(KeyMap{}, sailingKeyMap...)
+	fire, _ := km.find("fire")
+	if c := bind(&km, map[string][]string{"fire": {"M"}}); len(c["M"]) != 2 {
+		t.Fatalf("binding fire to the minimap's key should conflict, got %v", c)
+	}
+	if k, _ := km.find("fire"); len(k.key) != len(fire.key) || k.key[0] != fire.key[0] {
+		t.Fatalf("a conflicting rebind should leave the key map as it was, fire bound to %v", k.key)
+	}
+	if c := bind(&km, map[string][]string{"fire": {"V"}}); c == nil {
+		t.Fatalf("binding fire to salvage's key should conflict")
+	}
+	if c := bind(&km, map[string][]string{"fire": {"F8"}}); c != nil {
+		t.Fatalf("binding fire to a free key shouldn't conflict, got %v", c)
+	}
+	if k, _ := km.find("fire"); len(k.key) != 1 || k.key[0] != "F8" {
+		t.Fatalf("fire should be bound to F8, bound to %v", k.key)
+	}
+}
+
+func TestMigrateBindings(t *testing.T) {
+	actions, changed := sailingKeyMap.migrate(map[string][]string{"fire cannons": {"F8"}, "minimap": {"N"}})
+	if !changed || len(actions["fire"]) != 1 || len(actions["minimap"]) != 1 {
+		t.Fatalf("bindings saved by help text should be renamed to their actions, got %v", actions)
+	}
+	if _, changed := sailingKeyMap.migrate(map[string][]string{"fire": {"F8"}}); changed {
+		t.Fatalf("bindings saved by action name shouldn't need changing")
+	}
+	actions, changed = sailingKeyMap.migrate(map[string][]string{"help": {"?"}})
+	if !changed || len(actions["help"]) != 1 || actions["help"][0] != "/" {
+		t.Fatalf("help saved on ?, which fyne never reports, should move to /, got %v", actions["help"])
+	}
+}
+
+func TestHelpExitFollowsBinding(t *testing.T) {
+	saved := sailingKeyMap
+	t.Cleanup(func() {
+		sailingKeyMap = saved
+	})
+	if c := bind(&sailingKeyMap, map[string][]string{"help": {"F8"}}); c != nil {
+		t.Fatalf("binding help to a free key shouldn't conflict, got %v", c)
+	}
+	exit := helpKeyMapFor(world.ViewTypeMainMap)[0]
+	if strings.Join(exit.key, " ") != "F8 Escape" {
+		t.Fatalf("help opened while sailing should close on its rebound key or Escape, closes on %v", exit.key)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"pirate-wars/cmd/combat"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/faction"
+	"pirate-wars/cmd/npc"
+	"pirate-wars/cmd/ship"
+	"pirate-wars/cmd/spatial"
+	"pirate-wars/cmd/user_action"
+	"pirate-wars/cmd/world"
+)
+
+// boardingState an enemy ship the player has boarded and won the fight for
+type boardingState struct {
+	target    *npc.Npc
+	result    combat.BoardingResult
+	plundered bool
+	recruited bool
+}
+
+var BoardingData *boardingState
+
+// boardShip grapples an adjacent, weakened ship and fights for it
+func (gs *GameState) boardShip() {
+	pos := gs.player.GetPos()
+	for _, item := range gs.index.QueryRadius(pos, 1, spatial.KindNpc) {
+		n := item.(*npc.Npc)
+		if !common.IsPositionAdjacent(pos, n.GetPos()) || n.IsOwnedByPlayer() || !combat.CanBoard(n.GetShip()) {
+			continue
+		}
+		result := combat.Board(gs.player.GetShip(), n.GetShip())
+		gs.relations.AddNotoriety(n.GetFlag(), faction.BoardNotoriety)
+		gs.logger.Infof("Player boards [%v] %v: %+v", n.GetID(), n.GetPos(), result)
+		if !result.Won {
+			gs.player.GetShip().ChangeMorale(-ship.DefeatMorale)
+			n.Provoke()
+			notify(fmt.Sprintf("Boarding repelled! Lost %d crew", result.AttackerLosses))
+			return
+		}
+		gs.player.GetShip().ChangeMorale(ship.VictoryMorale)
+		gs.towns.ReportPiracy(n.GetPos())
+		notify(fmt.Sprintf("Boarded %s! Lost %d crew, killed %d", n.GetName(), result.AttackerLosses, result.DefenderLosses))
+		Action = user_action.UserActionIdBoard
+		ViewType = world.ViewTypeBoarding
+		BoardingData = &boardingState{target: n, result: result}
+		ExamineData = user_action.Examine()
+		ExamineData.AddItem(n)
+		return
+	}
+	notify("No weakened ship alongside to board")
+}
+
+func (gs *GameState) plunder() {
+	if BoardingData.plundered {
+		return
+	}
+	BoardingData.plundered = true
+	s := BoardingData.target.GetShip()
+	gold := s.TakeGold()
+	gs.player.AddGold(gold)
+	loaded := gs.player.GetShip().LoadGoods(s.GetHold())
+	for c, n := range loaded {
+		s.UnloadCargo(c, n)
+	}
+	cargo := loaded.Total()
+	if gold > 0 || cargo > 0 {
+		gs.player.GetShip().ChangeMorale(ship.PlunderMorale)
+	}
+	notify(fmt.Sprintf("Plundered %d gold and %d cargo", gold, cargo))
+	gs.findMap(fmt.Sprintf("the papers of %s", BoardingData.target.GetName()))
+}
+
+// recruit about half of the surviving crew are willing to join
+func (gs *GameState) recruit() {
+	if BoardingData.recruited {
+		return
+	}
+	BoardingData.recruited = true
+	s := BoardingData.target.GetShip()
+	added := gs.player.GetShip().AddCrew(s.GetCrew() / 2)
+	s.LoseCrew(added)
+	notify(fmt.Sprintf("Recruited %d crew", added))
+}
+
+func (gs *GameState) takePrize() {
+	BoardingData.target.Capture(gs.player, common.PlayerFlag, gs.npcs.FleetStation(BoardingData.target))
+	notify(fmt.Sprintf("Took %s as a prize", BoardingData.target.GetName()))
+	endBoarding()
+}
+
+func (gs *GameState) letGo() {
+	BoardingData.target.Release()
+	notify(fmt.Sprintf("Let %s go", BoardingData.target.GetName()))
+	endBoarding()
+}
+
+func endBoarding() {
+	BoardingData = nil
+	Action = user_action.UserActionIdNone
+	ViewType = world.ViewTypeMainMap
+	ExamineData = user_action.Examine()
+}
@@ -0,0 +1,22 @@
+package clock
+
+// TicksPerDay game ticks making up a single day at sea
+const TicksPerDay = 120
+
+type Clock struct {
+	ticks int
+}
+
+// Advance moves time on by a tick, returns true when a new day dawns
+func (c *Clock) Advance() bool {
+	c.ticks++
+	return c.ticks%TicksPerDay == 0
+}
+
+func (c *Clock) GetTicks() int {
+	return c.ticks
+}
+
+func (c *Clock) GetDay() int {
+	return c.ticks/TicksPerDay + 1
+}
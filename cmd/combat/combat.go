@@ -0,0 +1,143 @@
+package combat
+
+import (
+	"math/rand"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/ship"
+)
+
+// MaxHitChance percentage chance a cannon hits at point blank range
+const MaxHitChance = 85
+
+// MinHitChance percentage chance a cannon hits at the edge of its range
+const MinHitChance = 15
+
+// CannonDamage hull damage done by a single hit
+const CannonDamage = 2
+
+// RiggingHitChance percentage chance a hit tears through the sails rather than the hull
+const RiggingHitChance = 30
+
+// SailDamage damage done to the sails by a hit in the rigging
+const SailDamage = 3
+
+// MaxBoardingRounds rounds of melee before the side with the bigger share of its crew left prevails
+const MaxBoardingRounds = 50
+
+type Shot struct {
+	Target     common.Coordinates
+	Hits       int
+	Damage     int
+	SailDamage int
+	Casualties int
+}
+
+func (s Shot) IsHit() bool {
+	return s.Hits > 0
+}
+
+func InRange(attacker *ship.Ship, distance int) bool {
+	return distance > 0 && distance <= attacker.GetClass().Range
+}
+
+// Fortification a target on land, its walls take the hits
+type Fortification interface {
+	Bombard(damage int, casualties int)
+}
+
+// HitChance percentage chance of each cannon hitting a ship at the given distance
+func HitChance(attacker *ship.Ship, distance int) int {
+	if !InRange(attacker, distance) {
+		return 0
+	}
+	return hitChance(attacker.GetClass().Range, distance)
+}
+
+func hitChance(r int, distance int) int {
+	if distance <= 0 || distance > r {
+		return 0
+	}
+	return MaxHitChance - (MaxHitChance-MinHitChance)*(distance-1)/max(1, r-1)
+}
+
+// volley every cannon rolls to hit, and each hit damages the hull or sails and may kill some of the crew
+func volley(cannons int, chance int, targetPos common.Coordinates) Shot {
+	shot := Shot{Target: targetPos}
+	for i := 0; i < cannons; i++ {
+		if rand.Intn(100) < chance {
+			shot.Hits++
+			if rand.Intn(100) < RiggingHitChance {
+				shot.SailDamage += SailDamage
+			} else {
+				shot.Damage += CannonDamage
+			}
+		}
+	}
+	shot.Casualties = rand.Intn(shot.Hits + 1)
+	return shot
+}
+
+func (s Shot) apply(target *ship.Ship) {
+	target.Damage(s.Damage)
+	target.DamageSails(s.SailDamage)
+	target.LoseCrew(s.Casualties)
+}
+
+// Fire a broadside from attacker at target, with every manned cannon there's powder for
+func Fire(attacker *ship.Ship, target *ship.Ship, targetPos common.Coordinates, distance int) Shot {
+	shot := volley(attacker.UsePowder(attacker.GetGunsManned()), HitChance(attacker, distance), targetPos)
+	shot.apply(target)
+	attacker.Reload()
+	return shot
+}
+
+// FortFire a fort's cannons fire on a ship within their range
+func FortFire(cannons int, r int, target *ship.Ship, targetPos common.Coordinates, distance int) Shot {
+	shot := volley(cannons, hitChance(r, distance), targetPos)
+	shot.apply(target)
+	return shot
+}
+
+// Bombard a broadside from attacker at a fort, there's no rigging to miss so every hit strikes the walls
+func Bombard(attacker *ship.Ship, fort Fortification, fortPos common.Coordinates, distance int) Shot {
+	shot := volley(attacker.UsePowder(attacker.GetGunsManned()), HitChance(attacker, distance), fortPos)
+	shot.Damage += shot.SailDamage * CannonDamage / SailDamage
+	shot.SailDamage = 0
+	fort.Bombard(shot.Damage, shot.Casualties)
+	attacker.Reload()
+	return shot
+}
+
+type BoardingResult struct {
+	Won            bool
+	AttackerLosses int
+	DefenderLosses int
+}
+
+// CanBoard a ship can only be boarded once its hull or crew has been badly weakened
+func CanBoard(defender *ship.Ship) bool {
+	c := defender.GetClass()
+	return !defender.IsSunk() && (defender.GetHull()*2 <= c.Hull || defender.GetCrew()*2 <= c.Crew)
+}
+
+// Board resolves a crew-versus-crew fight, each round both sides lose men in proportion to the
+// other's strength, weakened by low morale, until one side is reduced to a quarter of the crew it
+// started with.
+func Board(attacker *ship.Ship, defender *ship.Ship) BoardingResult {
+	result := BoardingResult{}
+	startA, startD := attacker.GetCrew(), defender.GetCrew()
+	effortA, effortD := attacker.GetEffort(), defender.GetEffort()
+	a, d := startA, startD
+	for i := 0; i < MaxBoardingRounds && a*4 > startA && d*4 > startD; i++ {
+		lossD := min(d, rand.Intn(a*effortA/500+2))
+		lossA := min(a, rand.Intn(d*effortD/500+2))
+		d -= lossD
+		a -= lossA
+	}
+	result.Won = a > 0 && (d == 0 || a*startD > d*startA)
+	result.AttackerLosses = startA - a
+	result.DefenderLosses = startD - d
+	attacker.LoseCrew(result.AttackerLosses)
+	defender.LoseCrew(result.DefenderLosses)
+	return result
+}
@@ -0,0 +1,81 @@
+package combat
+
+import (
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/ship"
+	"testing"
+)
+
+func TestHitChance(t *testing.T) {
+	s := ship.Create(ship.ClassFrigate)
+	r := s.GetClass().Range
+	if c := HitChance(s, 1); c != MaxHitChance {
+		t.Fatalf("expected point blank hit chance %d, got %d", MaxHitChance, c)
+	}
+	if c := HitChance(s, r); c != MinHitChance {
+		t.Fatalf("expected hit chance at max range %d, got %d", MinHitChance, c)
+	}
+	if c := HitChance(s, r+1); c != 0 {
+		t.Fatalf("expected no chance to hit out of range, got %d", c)
+	}
+}
+
+func TestFire(t *testing.T) {
+	attacker := ship.Create(ship.ClassSloop)
+	target := ship.Create(ship.ClassGalleon)
+	shot := Fire(attacker, target, common.Coordinates{X: 1, Y: 1}, 1)
+	if target.GetHull() != target.GetClass().Hull-shot.Damage {
+		t.Fatalf("expected hull %d after %d damage, got %d", target.GetClass().Hull-shot.Damage, shot.Damage, target.GetHull())
+	}
+	if attacker.IsLoaded() {
+		t.Fatalf("attacker should be reloading after firing")
+	}
+	for i := 0; i < ship.ReloadTicks; i++ {
+		attacker.Tick()
+	}
+	if !attacker.IsLoaded() {
+		t.Fatalf("attacker should have reloaded after %d ticks", ship.ReloadTicks)
+	}
+}
+
+func TestBoard(t *testing.T) {
+	attacker := ship.Create(ship.ClassFrigate)
+	defender := ship.Create(ship.ClassSloop)
+	if CanBoard(defender) {
+		t.Fatalf("an undamaged ship should not be boardable")
+	}
+	defender.LoseCrew(defender.GetCrew() - 2)
+	if !CanBoard(defender) {
+		t.Fatalf("a ship with a decimated crew should be boardable")
+	}
+	result := Board(attacker, defender)
+	if !result.Won {
+		t.Fatalf("a full frigate crew should overwhelm 2 sailors: %+v", result)
+	}
+	if attacker.GetCrew() != attacker.GetClass().Crew-result.AttackerLosses {
+		t.Fatalf("attacker losses not applied: %+v", result)
+	}
+}
+
+type FortMock struct {
+	damage     int
+	casualties int
+}
+
+func (f *FortMock) Bombard(damage int, casualties int) {
+	f.damage += damage
+	f.casualties += casualties
+}
+
+func TestBombard(t *testing.T) {
+	attacker := ship.Create(ship.ClassFrigate)
+	fort := &FortMock{}
+	powder := attacker.GetSupply(ship.SupplyPowder)
+	shot := Bombard(attacker, fort, common.Coordinates{X: 1, Y: 1}, 1)
+	if shot.SailDamage != 0 || fort.damage != shot.Hits*CannonDamage || fort.casualties != shot.Casualties {
+		t.Fatalf("every hit should strike the walls, %d hits did %d damage", shot.Hits, fort.damage)
+	}
+	if attacker.GetSupply(ship.SupplyPowder) != powder-attacker.GetGunsManned() {
+		t.Fatalf("each cannon fired should use a shot of powder")
+	}
+}
@@ -34,6 +34,18 @@ var Flags = []Flag{
 	{Ship: ShipYellow, Name: "Spanish", Color: color.RGBA{231, 186, 35, 255}},
 }
 
+// PlayerFlag flown by the player's own ships
+var PlayerFlag = Flag{Ship: ShipWhite, Name: "Player", Color: color.White}
+
+// PirateFlag flown by mutineers, who answer to no nation
+var PirateFlag = Flags[0]
+
+var placePrefix = []string{"Port", "Isle of", "Saint", "South", "North", "East", "West", "Mt"}
+var place = []string{"Coxswain", "Rackham", "Seezley", "Salty", "Briller", "Dunstan", "Cordith", "Firth", "Barbady",
+	"Yorben", "Nillith", "Sanctitly", "Laction", "Derzley", "Jitterham", "Milktown", "Appleton", "Greently", "Asstin",
+	"Hoplonton", "Welgadin", "Klappertown", "Windville", "Folkenwald", "Dids", "Munkton", "Shallows", "Plaqard", "Oiltown", "Willows", "Quellton"}
+var placeSuffix = []string{"Bay", "Island", "Falls", "Harbour", "Lake", "River", "Way", "Rock", "Springs", "Bend", "Beach", "Point"}
+
 func roll() bool {
 	return rand.Intn(2) == 0
 }
@@ -56,11 +68,6 @@ func GenerateCaptainName() string {
 	var flairWithThe = []string{"Dishonest", "Soft Heart", "Balding", "Rum Lover", "Hair", "Gloomy",
 		"Cutthroat", "Dastardly", "Vile", "Ripe", "Pungent", "Piggy", "Pleasant", "Crazy", "Weasel", "Squealer",
 		"Snake", "Slayer", "Traitor", "Coxswain", "One-tooth", "Windy", "Butter", "Cozy", "Tide Turner", "Bear", "Savage"}
-	var placePrefix = []string{"Port", "Isle of", "Saint", "South", "North", "East", "West", "Mt"}
-	var place = []string{"Coxswain", "Rackham", "Seezley", "Salty", "Briller", "Dunstan", "Cordith", "Firth", "Barbady",
-		"Yorben", "Nillith", "Sanctitly", "Laction", "Derzley", "Jitterham", "Milktown", "Appleton", "Greently", "Asstin",
-		"Hoplonton", "Welgadin", "Klappertown", "Windville", "Folkenwald", "Dids", "Munkton", "Shallows", "Plaqard", "Oiltown", "Willows", "Quellton"}
-	var placeSuffix = []string{"Bay", "Island", "Falls", "Harbour", "Lake", "River", "Way", "Rock", "Springs", "Bend", "Beach", "Point"}
 
 	fullName := []string{}
 	last := "none"
@@ -108,6 +115,19 @@ func GenerateCaptainName() string {
 	return cases.Title(language.English).String(strings.Join(fullName, " "))
 }
 
+// GeneratePlaceName a name for a town, from the same places captains hail from
+func GeneratePlaceName() string {
+	fullName := []string{}
+	if roll() {
+		fullName = append(fullName, grab(placePrefix))
+	}
+	fullName = append(fullName, grab(place))
+	if roll() {
+		fullName = append(fullName, grab(placeSuffix))
+	}
+	return strings.Join(fullName, " ")
+}
+
 func GetRandomFlag() Flag {
 	return Flags[rand.Intn(len(Flags))]
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"image/color"
 	"math/rand"
+	"sync/atomic"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -13,6 +14,8 @@ import (
 
 const (
 	LogFile        = "pirate-wars.log"
+	SaveFile       = "pirate-wars.sav"
+	KeysFile       = "pirate-wars-keys.json"
 	WorldCols  int = 800 // Y
 	WorldRows  int = 800 // X
 	TotalTowns     = 30
@@ -36,11 +39,30 @@ var Directions = []Coordinates{
 	{0, 1},   // right
 }
 
-var letterRunes = []rune("abcdefghijklmnopqrstuvwxyz")
+// Compass points clockwise from north
+var Compass = []Coordinates{
+	{X: 0, Y: -1}, {X: 1, Y: -1}, {X: 1, Y: 0}, {X: 1, Y: 1},
+	{X: 0, Y: 1}, {X: -1, Y: 1}, {X: -1, Y: 0}, {X: -1, Y: -1},
+}
+
+var CompassNames = []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
 
+// CompassPoint the index of the compass point d heads towards, or -1 if it isn't one
+func CompassPoint(d Coordinates) int {
+	for i, c := range Compass {
+		if CoordsMatch(c, d) {
+			return i
+		}
+	}
+	return -1
+}
+
+// lastID the sequence number of the last ID handed out, so no two entities ever share one
+var lastID atomic.Int64
+
+// GenID an ID unique to the entity, prefixed with the position it was created at
 func GenID(pos Coordinates) string {
-	b := letterRunes[rand.Intn(len(letterRunes))]
-	return fmt.Sprintf("%v%03d%03d", string(b), pos.X, pos.Y)
+	return fmt.Sprintf("%03d%03d-%d", pos.X, pos.Y, lastID.Add(1))
 }
 
 func Inbounds(c Coordinates) bool {
@@ -78,6 +100,11 @@ func ClosestTo(d Coordinates, p []Coordinates) Coordinates {
 	return closest
 }
 
+// Distance number of moves between two positions, diagonal moves included
+func Distance(a, b Coordinates) int {
+	return max(diff(a.X, b.X), diff(a.Y, b.Y))
+}
+
 func diff(a, b int) int {
 	if a < b {
 		return b - a
@@ -0,0 +1,15 @@
+package common
+
+import "testing"
+
+func TestGenID(t *testing.T) {
+	pos := Coordinates{X: 12, Y: 34}
+	seen := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		id := GenID(pos)
+		if seen[id] {
+			t.Fatalf("ID %s handed out twice for entities created at %v", id, pos)
+		}
+		seen[id] = true
+	}
+}
@@ -12,6 +12,7 @@ const (
 	TerrainTypeTown         = 8
 	TerrainTypeGhostTown    = 9
 	TerrainTypeLowlandBrush = 10
+	TerrainTypeHideout      = 11
 )
 
 type TerrainType int
@@ -0,0 +1,429 @@
+package contract
+
+import (
+	"fmt"
+	"math/rand"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/economy"
+	"pirate-wars/cmd/faction"
+	"pirate-wars/cmd/npc"
+	"pirate-wars/cmd/ship"
+	"pirate-wars/cmd/town"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// OffersPerTown contracts a town's governor has on offer at a time
+const OffersPerTown = 3
+
+// OfferDays days an offer stands before the governor finds someone else
+const OfferDays = 5
+
+// ContractRange tiles from the issuing town within which its contracts send the player
+const ContractRange = 200
+
+// TilesPerDay tiles a ship is reckoned to sail in a day, when setting deadlines
+const TilesPerDay = 40
+
+// EscortRange tiles from the convoy the player has to be when it makes port
+const EscortRange = 10
+
+// ConvoyRange tiles from the issuing town within which a convoy is still gathering
+const ConvoyRange = 15
+
+// DeliveryLot units of goods asked for in a delivery
+const DeliveryLot = 30
+
+type ContractType int
+
+const (
+	ContractDeliver   = 1
+	ContractHunt      = 2
+	ContractEscort    = 3
+	ContractPassenger = 4
+)
+
+// Terms what each kind of contract pays, in gold per tile sailed or per cannon fought, and in
+// notoriety forgiven by the issuing nation
+type Terms struct {
+	Name       string
+	Days       int
+	Reward     int
+	Reputation int
+}
+
+var TermsLookup = map[ContractType]Terms{
+	ContractDeliver:   {Name: "Delivery", Days: 3, Reward: 3, Reputation: 5},
+	ContractHunt:      {Name: "Hunt", Days: 10, Reward: 40, Reputation: 15},
+	ContractEscort:    {Name: "Escort", Days: 3, Reward: 5, Reputation: 10},
+	ContractPassenger: {Name: "Passage", Days: 2, Reward: 4, Reputation: 5},
+}
+
+type Status int
+
+const (
+	StatusOffered   = 0
+	StatusActive    = 1
+	StatusCompleted = 2
+	StatusFailed    = 3
+)
+
+var StatusLookup = map[Status]string{
+	StatusOffered:   "Offered",
+	StatusActive:    "Active",
+	StatusCompleted: "Completed",
+	StatusFailed:    "Failed",
+}
+
+// Contract work offered by a town's governor, aimed at real towns and ships, which resolves itself
+// once its conditions are met or its deadline passes
+type Contract struct {
+	kind      ContractType
+	issuer    *town.Town
+	dest      *town.Town
+	target    *npc.Npc
+	commodity economy.CommodityType
+	units     int
+	passenger string
+	reward    int
+	offered   int
+	deadline  int
+	status    Status
+	outcome   string
+}
+
+// Situation where the player stands when contracts are checked
+type Situation struct {
+	Day    int
+	Pos    common.Coordinates
+	Docked *town.Town
+	Ship   *ship.Ship
+}
+
+type Contracts struct {
+	logger    *zap.SugaredLogger
+	towns     *town.Towns
+	npcs      *npc.Npcs
+	relations *faction.Relations
+	offers    map[string][]*Contract
+	list      []*Contract
+}
+
+func (c *Contract) GetType() ContractType {
+	return c.kind
+}
+
+func (c *Contract) GetIssuer() *town.Town {
+	return c.issuer
+}
+
+func (c *Contract) GetReward() int {
+	return c.reward
+}
+
+// GetReputation notoriety the issuing nation forgives on completion, and adds on failure
+func (c *Contract) GetReputation() int {
+	return TermsLookup[c.kind].Reputation
+}
+
+func (c *Contract) GetDeadline() int {
+	return c.deadline
+}
+
+func (c *Contract) GetStatus() Status {
+	return c.status
+}
+
+func (c *Contract) GetOutcome() string {
+	return c.outcome
+}
+
+// GetSummary what the governor asks of the player
+func (c *Contract) GetSummary() string {
+	switch c.kind {
+	case ContractDeliver:
+		return fmt.Sprintf("Deliver %d %s to %s", c.units, strings.ToLower(economy.CommodityLookup[c.commodity].Name), c.dest.GetName())
+	case ContractHunt:
+		return fmt.Sprintf("Sink or take the %s %s of %s", c.target.GetFlag(), strings.ToLower(c.target.GetShip().GetClass().Name), c.target.GetName())
+	case ContractEscort:
+		return fmt.Sprintf("Escort the %s %s of %s to %s", c.target.GetFlag(), strings.ToLower(c.target.GetShip().GetClass().Name), c.target.GetName(), c.dest.GetName())
+	case ContractPassenger:
+		return fmt.Sprintf("Carry %s to %s", c.passenger, c.dest.GetName())
+	}
+	return ""
+}
+
+// GetDetails the summary with its terms
+func (c *Contract) GetDetails() string {
+	return fmt.Sprintf("%s by day %d - %d gold, %d reputation with the %s",
+		c.GetSummary(), c.deadline, c.reward, c.GetReputation(), c.issuer.GetFlag())
+}
+
+// resolve checks the contract against the player's situation, returns whether it's settled
+func (c *Contract) resolve(s Situation) bool {
+	switch c.kind {
+	case ContractDeliver:
+		if c.docked(s) && s.Ship.GetHold()[c.commodity] >= c.units {
+			s.Ship.UnloadCargo(c.commodity, c.units)
+			c.dest.GetMarket().Sell(c.commodity, c.units)
+			return c.settle(StatusCompleted, fmt.Sprintf("Delivered %d %s to %s", c.units, strings.ToLower(economy.CommodityLookup[c.commodity].Name), c.dest.GetName()))
+		}
+	case ContractHunt:
+		if c.target.IsSunkByPlayer() || c.target.IsOwnedByPlayer() {
+			return c.settle(StatusCompleted, fmt.Sprintf("%s is no longer a threat", c.target.GetName()))
+		}
+		if c.target.IsSinking() {
+			return c.settle(StatusFailed, fmt.Sprintf("%s went down before you could catch her", c.target.GetName()))
+		}
+	case ContractEscort:
+		if c.target.IsSinking() || c.target.IsOwnedByPlayer() {
+			return c.settle(StatusFailed, fmt.Sprintf("The convoy of %s was lost", c.target.GetName()))
+		}
+		if dest, ok := c.target.GetDestination(); !ok || dest.GetID() != c.dest.GetID() {
+			if common.Distance(s.Pos, c.target.GetPos()) > EscortRange {
+				return c.settle(StatusFailed, fmt.Sprintf("The convoy of %s made %s without you", c.target.GetName(), c.dest.GetName()))
+			}
+			return c.settle(StatusCompleted, fmt.Sprintf("Escorted %s safely to %s", c.target.GetName(), c.dest.GetName()))
+		}
+	case ContractPassenger:
+		if c.docked(s) {
+			return c.settle(StatusCompleted, fmt.Sprintf("%s went ashore at %s", c.passenger, c.dest.GetName()))
+		}
+	}
+	if s.Day > c.deadline {
+		return c.settle(StatusFailed, fmt.Sprintf("Missed the deadline to %s", strings.ToLower(c.GetSummary()[:1])+c.GetSummary()[1:]))
+	}
+	return false
+}
+
+func (c *Contract) docked(s Situation) bool {
+	return s.Docked != nil && s.Docked.GetID() == c.dest.GetID()
+}
+
+func (c *Contract) settle(status Status, outcome string) bool {
+	c.status = status
+	c.outcome = outcome
+	return true
+}
+
+// GetOffers the contracts the town's governor has on offer, drawn up afresh once the old ones
+// have been taken or have lapsed
+func (cs *Contracts) GetOffers(t *town.Town, day int) []*Contract {
+	if len(cs.offers[t.GetID()]) == 0 {
+		cs.offers[t.GetID()] = cs.drawUp(t, day)
+	}
+	return cs.offers[t.GetID()]
+}
+
+func (cs *Contracts) drawUp(t *town.Town, day int) []*Contract {
+	offers := []*Contract{}
+	for i := 0; i < OffersPerTown; i++ {
+		c, ok := cs.draw(ContractType(rand.Intn(len(TermsLookup))+ContractDeliver), t, day)
+		if ok {
+			offers = append(offers, c)
+		}
+	}
+	return offers
+}
+
+// draw a contract of the given kind, if the town has a destination or a ship to aim it at
+func (cs *Contracts) draw(kind ContractType, t *town.Town, day int) (*Contract, bool) {
+	c := &Contract{kind: kind, issuer: t, offered: day, status: StatusOffered}
+	terms := TermsLookup[kind]
+	switch kind {
+	case ContractDeliver:
+		dest, ok := cs.destination(t)
+		if !ok {
+			return nil, false
+		}
+		c.dest = dest
+		c.commodity = mostWanted(dest.GetMarket())
+		c.units = DeliveryLot
+		c.reward = c.units*dest.GetMarket().GetPrice(c.commodity) + common.Distance(t.GetPos(), dest.GetPos())*terms.Reward
+	case ContractHunt:
+		target, ok := cs.quarry(t)
+		if !ok {
+			return nil, false
+		}
+		c.target = target
+		c.reward = target.GetShip().GetClass().Cannons * terms.Reward
+	case ContractEscort:
+		target, dest, ok := cs.convoy(t)
+		if !ok {
+			return nil, false
+		}
+		c.target = target
+		c.dest = dest
+		c.reward = common.Distance(t.GetPos(), dest.GetPos()) * terms.Reward
+	case ContractPassenger:
+		dest, ok := cs.destination(t)
+		if !ok {
+			return nil, false
+		}
+		c.dest = dest
+		c.passenger = common.GenerateCaptainName()
+		c.reward = common.Distance(t.GetPos(), dest.GetPos()) * terms.Reward
+	}
+	sail := 0
+	if c.kind != ContractHunt {
+		sail = common.Distance(t.GetPos(), c.dest.GetPos()) / TilesPerDay
+	}
+	c.deadline = day + terms.Days + sail
+	return c, true
+}
+
+// destination a town still lived in within range of t
+func (cs *Contracts) destination(t *town.Town) (*town.Town, bool) {
+	candidates := []*town.Town{}
+	towns := cs.towns.GetTowns()
+	for i := range towns {
+		o := &towns[i]
+		if o.GetID() != t.GetID() && !o.IsGhostTown() && common.Distance(t.GetPos(), o.GetPos()) <= ContractRange {
+			candidates = append(candidates, o)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, false
+	}
+	return candidates[rand.Intn(len(candidates))], true
+}
+
+// quarry a ship of the town's enemies within range
+func (cs *Contracts) quarry(t *town.Town) (*npc.Npc, bool) {
+	candidates := []*npc.Npc{}
+	for _, n := range cs.npcs.GetList() {
+		if n.IsSinking() || n.IsOwnedByPlayer() || common.Distance(t.GetPos(), n.GetPos()) > ContractRange {
+			continue
+		}
+		if cs.relations.IsHostile(t.GetFlag(), n.GetFlag()) && cs.isFree(n) {
+			candidates = append(candidates, n)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, false
+	}
+	return candidates[rand.Intn(len(candidates))], true
+}
+
+// convoy a trader of the town's own nation setting out from it
+func (cs *Contracts) convoy(t *town.Town) (*npc.Npc, *town.Town, bool) {
+	for _, n := range cs.npcs.GetList() {
+		if n.IsSinking() || n.GetFlag() != t.GetFlag() || common.Distance(t.GetPos(), n.GetPos()) > ConvoyRange || !cs.isFree(n) {
+			continue
+		}
+		if dest, ok := n.GetDestination(); ok && dest.GetID() != t.GetID() && !dest.IsGhostTown() {
+			// the trader sails by its own copy of the town, the contract follows the town itself
+			if dest, ok := cs.towns.GetTown(dest.GetID()); ok {
+				return n, dest, true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// isFree whether no other contract has been aimed at the ship
+func (cs *Contracts) isFree(n *npc.Npc) bool {
+	for _, c := range cs.list {
+		if c.target == n && c.status == StatusActive {
+			return false
+		}
+	}
+	for _, offers := range cs.offers {
+		for _, c := range offers {
+			if c.target == n {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// mostWanted the dearest of the goods the market's town consumes
+func mostWanted(m *economy.Market) economy.CommodityType {
+	wanted := []economy.CommodityType{}
+	for _, c := range economy.Commodities() {
+		if m.GetConsumption(c) > 0 {
+			wanted = append(wanted, c)
+		}
+	}
+	if len(wanted) == 0 {
+		return economy.RandomCommodity()
+	}
+	sort.Slice(wanted, func(i, j int) bool {
+		return m.GetPrice(wanted[i]) > m.GetPrice(wanted[j])
+	})
+	return wanted[0]
+}
+
+// Accept the player signs the contract, taking it off the governor's offers
+func (cs *Contracts) Accept(c *Contract) {
+	id := c.issuer.GetID()
+	for i, o := range cs.offers[id] {
+		if o == c {
+			cs.offers[id] = append(cs.offers[id][:i], cs.offers[id][i+1:]...)
+			break
+		}
+	}
+	c.status = StatusActive
+	cs.list = append(cs.list, c)
+	cs.logger.Infof("Contract accepted: %s", c.GetDetails())
+}
+
+// Resolve checks the active contracts against the player's situation, returns those settled
+func (cs *Contracts) Resolve(s Situation) []*Contract {
+	settled := []*Contract{}
+	for _, c := range cs.list {
+		if c.status == StatusActive && c.resolve(s) {
+			cs.logger.Infof("Contract %s: %s", StatusLookup[c.status], c.outcome)
+			settled = append(settled, c)
+		}
+	}
+	return settled
+}
+
+// GetActive contracts the player is still working on
+func (cs *Contracts) GetActive() []*Contract {
+	return cs.filter(func(c *Contract) bool { return c.status == StatusActive })
+}
+
+// GetFinished contracts completed or failed
+func (cs *Contracts) GetFinished() []*Contract {
+	return cs.filter(func(c *Contract) bool { return c.status != StatusActive })
+}
+
+func (cs *Contracts) filter(fn func(c *Contract) bool) []*Contract {
+	list := []*Contract{}
+	for _, c := range cs.list {
+		if fn(c) {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+// NewDay governors withdraw lapsed offers, and those whose ship has already met its fate
+func (cs *Contracts) NewDay(day int) {
+	for id, offers := range cs.offers {
+		current := []*Contract{}
+		for _, c := range offers {
+			if day-c.offered >= OfferDays || (c.target != nil && (c.target.IsSinking() || c.target.IsOwnedByPlayer())) {
+				continue
+			}
+			current = append(current, c)
+		}
+		cs.offers[id] = current
+	}
+}
+
+func Init(towns *town.Towns, npcs *npc.Npcs, relations *faction.Relations, logger *zap.SugaredLogger) *Contracts {
+	return &Contracts{
+		logger:    logger,
+		towns:     towns,
+		npcs:      npcs,
+		relations: relations,
+		offers:    map[string][]*Contract{},
+		list:      []*Contract{},
+	}
+}
@@ -0,0 +1,89 @@
+package contract
+
+import (
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/faction"
+	"pirate-wars/cmd/npc"
+	"pirate-wars/cmd/ship"
+	"pirate-wars/cmd/spatial"
+	"pirate-wars/cmd/town"
+	"pirate-wars/cmd/world"
+	"pirate-wars/cmd/wreck"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"go.uber.org/zap"
+)
+
+type leaderMock struct{}
+
+func (l *leaderMock) GetPos() common.Coordinates { return common.Coordinates{} }
+
+func TestContracts(t *testing.T) {
+	test.NewApp()
+	test.NewWindow(nil)
+	logger := zap.NewNop().Sugar()
+	index := spatial.New()
+	w := world.Init(logger)
+	ts := town.Init(w, index, logger)
+	ns := npc.Init(ts, w, index, wreck.Init(index, logger), logger)
+	cs := Init(ts, ns, faction.Init(), logger)
+	issuer := &ts.GetTowns()[0]
+
+	c, ok := cs.draw(ContractDeliver, issuer, 1)
+	if !ok {
+		t.Fatalf("%s should have a town in range to deliver to", issuer.GetName())
+	}
+	cs.Accept(c)
+	s := ship.Create(ship.ClassGalleon)
+	dest := c.dest
+	situation := Situation{Day: 1, Docked: dest, Ship: s}
+	if len(cs.Resolve(situation)) != 0 {
+		t.Fatalf("a delivery shouldn't be settled without the goods aboard")
+	}
+	stock := dest.GetMarket().GetStock(c.commodity)
+	s.LoadCargo(c.commodity, c.units)
+	if settled := cs.Resolve(situation); len(settled) != 1 || c.GetStatus() != StatusCompleted {
+		t.Fatalf("delivering the goods to %s should complete the contract", dest.GetName())
+	}
+	if s.GetCargo() != 0 || dest.GetMarket().GetStock(c.commodity) != stock+c.units {
+		t.Fatalf("the goods should have been unloaded into %s's market", dest.GetName())
+	}
+
+	c, _ = cs.draw(ContractPassenger, issuer, 1)
+	cs.Accept(c)
+	if len(cs.Resolve(Situation{Day: c.GetDeadline(), Ship: s})) != 0 {
+		t.Fatalf("a passage shouldn't fail before its deadline")
+	}
+	if cs.Resolve(Situation{Day: c.GetDeadline() + 1, Ship: s}); c.GetStatus() != StatusFailed {
+		t.Fatalf("a passage should fail once its deadline has passed")
+	}
+
+	target := ns.GetList()[0]
+	c = &Contract{kind: ContractHunt, issuer: issuer, target: target, deadline: 10}
+	cs.Accept(c)
+	if !cs.isFree(ns.GetList()[1]) || cs.isFree(target) {
+		t.Fatalf("only one contract at a time should be aimed at a ship")
+	}
+	target.Capture(&leaderMock{}, common.PlayerFlag, npc.FormationOffset(0))
+	if cs.Resolve(Situation{Day: 2, Ship: s}); c.GetStatus() != StatusCompleted {
+		t.Fatalf("taking the quarry should complete the hunt")
+	}
+	lost, sunk := ns.GetList()[1], ns.GetList()[2]
+	c = &Contract{kind: ContractHunt, issuer: issuer, target: lost, deadline: 10}
+	cs.Accept(c)
+	lost.GetShip().Damage(lost.GetShip().GetHull())
+	if cs.Resolve(Situation{Day: 2, Ship: s}); c.GetStatus() != StatusFailed {
+		t.Fatalf("the quarry going down to someone else's guns shouldn't complete the hunt")
+	}
+	c = &Contract{kind: ContractHunt, issuer: issuer, target: sunk, deadline: 10}
+	cs.Accept(c)
+	sunk.GetShip().Damage(sunk.GetShip().GetHull())
+	sunk.TakePlayerFire()
+	if cs.Resolve(Situation{Day: 2, Ship: s}); c.GetStatus() != StatusCompleted {
+		t.Fatalf("sinking the quarry should complete the hunt")
+	}
+	if len(cs.GetActive()) != 0 || len(cs.GetFinished()) != 5 {
+		t.Fatalf("every contract should be finished, %d active", len(cs.GetActive()))
+	}
+}
@@ -0,0 +1,181 @@
+package economy
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// TargetStock stock of a commodity at which a market pays its base price
+const TargetStock = 100
+
+// MaxStock stock a market holds before it stops taking more of a commodity
+const MaxStock = TargetStock * 5
+
+// SellShare percentage of its asking price a market pays for goods sold to it
+const SellShare = 80
+
+// ProducedCommodities commodities each town produces, and as many again it consumes
+const ProducedCommodities = 2
+
+const (
+	CommoditySugar   = 1
+	CommodityRum     = 2
+	CommodityTobacco = 3
+	CommodityCloth   = 4
+	CommodityTimber  = 5
+	CommoditySpices  = 6
+)
+
+type CommodityType int
+
+type Commodity struct {
+	Name      string
+	BasePrice int
+}
+
+var CommodityLookup = map[CommodityType]Commodity{
+	CommoditySugar:   {Name: "Sugar", BasePrice: 10},
+	CommodityRum:     {Name: "Rum", BasePrice: 15},
+	CommodityTobacco: {Name: "Tobacco", BasePrice: 20},
+	CommodityCloth:   {Name: "Cloth", BasePrice: 12},
+	CommodityTimber:  {Name: "Timber", BasePrice: 6},
+	CommoditySpices:  {Name: "Spices", BasePrice: 30},
+}
+
+// Commodities every commodity type, in order
+func Commodities() []CommodityType {
+	list := make([]CommodityType, 0, len(CommodityLookup))
+	for c := range CommodityLookup {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+func RandomCommodity() CommodityType {
+	return CommodityType(rand.Intn(len(CommodityLookup)) + CommoditySugar)
+}
+
+// Goods quantities of commodities, e.g. a ship's hold
+type Goods map[CommodityType]int
+
+func (g Goods) Total() int {
+	total := 0
+	for _, n := range g {
+		total += n
+	}
+	return total
+}
+
+// Take removes up to n units, commodity by commodity, and returns them
+func (g Goods) Take(n int) Goods {
+	taken := Goods{}
+	for _, c := range Commodities() {
+		t := min(n, g[c])
+		if t > 0 {
+			taken[c] = t
+			g[c] -= t
+			n -= t
+		}
+	}
+	return taken
+}
+
+func (g Goods) Add(o Goods) {
+	for c, n := range o {
+		g[c] += n
+	}
+}
+
+// Market a town's stock of each commodity, which its producers add to and its people use up
+type Market struct {
+	stock       Goods
+	production  Goods
+	consumption Goods
+	traded      int
+}
+
+// CreateMarket a market with a few commodities produced locally and others in demand
+func CreateMarket() *Market {
+	m := Market{stock: Goods{}, production: Goods{}, consumption: Goods{}}
+	list := Commodities()
+	rand.Shuffle(len(list), func(i, j int) { list[i], list[j] = list[j], list[i] })
+	for i, c := range list {
+		m.stock[c] = TargetStock
+		if i < ProducedCommodities {
+			m.production[c] = rand.Intn(10) + 5
+			m.stock[c] = TargetStock * 2
+		} else if i < ProducedCommodities*2 {
+			m.consumption[c] = rand.Intn(10) + 5
+			m.stock[c] = TargetStock / 2
+		}
+	}
+	return &m
+}
+
+func (m *Market) GetStock(c CommodityType) int {
+	return m.stock[c]
+}
+
+func (m *Market) GetProduction(c CommodityType) int {
+	return m.production[c]
+}
+
+func (m *Market) GetConsumption(c CommodityType) int {
+	return m.consumption[c]
+}
+
+// GetPrice the asking price, the scarcer the commodity the dearer it gets
+func (m *Market) GetPrice(c CommodityType) int {
+	return max(1, CommodityLookup[c].BasePrice*2*TargetStock/(m.stock[c]+TargetStock))
+}
+
+// GetSellPrice what the market pays for a unit of the commodity
+func (m *Market) GetSellPrice(c CommodityType) int {
+	return max(1, m.GetPrice(c)*SellShare/100)
+}
+
+// Buy takes up to n units from the market's stock, returns how many there were
+func (m *Market) Buy(c CommodityType, n int) int {
+	bought := max(0, min(n, m.stock[c]))
+	m.stock[c] -= bought
+	m.traded += bought
+	return bought
+}
+
+// Sell adds up to n units to the market's stock, returns how many it took
+func (m *Market) Sell(c CommodityType, n int) int {
+	sold := max(0, min(n, MaxStock-m.stock[c]))
+	m.stock[c] += sold
+	m.traded += sold
+	return sold
+}
+
+// IsSurplus whether the market has more of the commodity than it needs
+func (m *Market) IsSurplus(c CommodityType) bool {
+	return m.stock[c] > TargetStock
+}
+
+// GetTraded units bought and sold since the start of the day
+func (m *Market) GetTraded() int {
+	return m.traded
+}
+
+// GetShortages needed commodities the market has run out of
+func (m *Market) GetShortages() int {
+	n := 0
+	for c, used := range m.consumption {
+		if used > 0 && m.stock[c] == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// NewDay producers bring their goods to market, and the town uses up what it consumes
+func (m *Market) NewDay() {
+	m.traded = 0
+	for c := range CommodityLookup {
+		m.stock[c] = min(MaxStock, max(0, m.stock[c]+m.production[c]-m.consumption[c]))
+	}
+}
@@ -0,0 +1,32 @@
+package economy
+
+import "testing"
+
+func TestPrice(t *testing.T) {
+	m := CreateMarket()
+	c := CommodityType(CommoditySpices)
+	m.stock[c] = TargetStock
+	if p := m.GetPrice(c); p != CommodityLookup[c].BasePrice {
+		t.Fatalf("expected the base price %d at the target stock, got %d", CommodityLookup[c].BasePrice, p)
+	}
+	m.Buy(c, TargetStock/2)
+	if m.GetPrice(c) <= CommodityLookup[c].BasePrice {
+		t.Fatalf("prices should rise as stock runs low")
+	}
+	m.Sell(c, TargetStock*2)
+	if m.GetPrice(c) >= CommodityLookup[c].BasePrice || m.GetSellPrice(c) >= m.GetPrice(c) {
+		t.Fatalf("prices should fall with a glut, and the market should pay less than it asks")
+	}
+}
+
+func TestGoods(t *testing.T) {
+	g := Goods{CommoditySugar: 5, CommodityRum: 10}
+	taken := g.Take(8)
+	if taken.Total() != 8 || g.Total() != 7 {
+		t.Fatalf("expected to take 8 of 15 units, took %d leaving %d", taken.Total(), g.Total())
+	}
+	g.Add(taken)
+	if g.Total() != 15 {
+		t.Fatalf("expected all 15 units back, got %d", g.Total())
+	}
+}
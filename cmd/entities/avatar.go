@@ -15,6 +15,12 @@ type Avatar struct {
 	color     color.Color
 	blink     bool
 	alternate bool
+	tracker   PositionTracker
+}
+
+// PositionTracker is told whenever an avatar moves, e.g. a spatial index
+type PositionTracker interface {
+	Move(id string, c common.Coordinates)
 }
 
 type AvatarReadOnly interface {
@@ -35,9 +41,16 @@ func (a *Avatar) SetPos(c common.Coordinates) {
 	if !common.CoordsMatch(a.pos, c) {
 		a.prevPos = a.pos
 		a.pos = c
+		if a.tracker != nil {
+			a.tracker.Move(a.id, c)
+		}
 	}
 }
 
+func (a *Avatar) Track(t PositionTracker) {
+	a.tracker = t
+}
+
 func (a *Avatar) GetPos() common.Coordinates {
 	return a.pos
 }
@@ -66,6 +79,14 @@ func (a *Avatar) GetColor() color.Color {
 	return a.color
 }
 
+func (a *Avatar) SetColor(c color.Color) {
+	a.color = c
+}
+
+func (a *Avatar) SetTileImage(i image.Image) {
+	a.image = i
+}
+
 func (a *Avatar) GetViewableRange() window.Dimensions {
 	return window.Dimensions{
 		Width:  20,
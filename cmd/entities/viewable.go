@@ -23,6 +23,16 @@ type ViewableEntity interface {
 	GetViewableRange() window.Dimensions
 }
 
+// DetailedEntity entities with more to tell when examined
+type DetailedEntity interface {
+	GetDetails() string
+}
+
+// SummarisedEntity entities that can be described in a line, e.g. in a list
+type SummarisedEntity interface {
+	GetSummary() string
+}
+
 type EmptyViewableEntity struct{}
 
 func (e *EmptyViewableEntity) GetPos() common.Coordinates {
@@ -0,0 +1,95 @@
+package faction
+
+import (
+	"math/rand"
+	"pirate-wars/cmd/common"
+)
+
+// Allied standing between a nation and itself
+const Allied = 100
+
+// Hostile standing below which a nation's forts fire on the other's ships
+const Hostile = -50
+
+// WarChance percentage chance of two nations being at war when the game starts
+const WarChance = 25
+
+// WantedNotoriety notoriety with a nation from which its forts fire on the player
+const WantedNotoriety = 20
+
+// AttackNotoriety notoriety earned with a nation by firing upon one of its ships or forts
+const AttackNotoriety = 5
+
+// BoardNotoriety notoriety earned with a nation by boarding one of its ships
+const BoardNotoriety = 15
+
+// NotorietyDecay notoriety forgotten each day
+const NotorietyDecay = 1
+
+// Relations the standing of the nations with each other, and how notorious the player is with each
+type Relations struct {
+	standing  map[string]map[string]int
+	notoriety map[string]int
+}
+
+// Init nations start out at peace with each other, apart from a few wars, pirates are at war
+// with everyone
+func Init() *Relations {
+	r := Relations{standing: map[string]map[string]int{}, notoriety: map[string]int{}}
+	for _, a := range common.Flags {
+		r.standing[a.Name] = map[string]int{}
+	}
+	for i, a := range common.Flags {
+		for _, b := range common.Flags[i+1:] {
+			s := rand.Intn(Allied)
+			if a.Name == common.PirateFlag.Name || b.Name == common.PirateFlag.Name || rand.Intn(100) < WarChance {
+				s = -Allied
+			}
+			r.standing[a.Name][b.Name] = s
+			r.standing[b.Name][a.Name] = s
+		}
+	}
+	return &r
+}
+
+// GetStanding how nation a regards nation b
+func (r *Relations) GetStanding(a, b string) int {
+	if a == b {
+		return Allied
+	}
+	return r.standing[a][b]
+}
+
+// IsHostile whether a's forts fire on b's ships, the player's own ships are judged by the
+// player's notoriety
+func (r *Relations) IsHostile(a, b string) bool {
+	if b == common.PlayerFlag.Name {
+		return r.IsWanted(a)
+	}
+	return r.GetStanding(a, b) < Hostile
+}
+
+func (r *Relations) GetNotoriety(nation string) int {
+	return r.notoriety[nation]
+}
+
+func (r *Relations) AddNotoriety(nation string, n int) {
+	r.notoriety[nation] += n
+}
+
+// Forgive the nation overlooks up to n of the player's notoriety
+func (r *Relations) Forgive(nation string, n int) {
+	r.notoriety[nation] = max(0, r.notoriety[nation]-n)
+}
+
+// IsWanted whether the nation's forts fire on the player
+func (r *Relations) IsWanted(nation string) bool {
+	return r.notoriety[nation] >= WantedNotoriety
+}
+
+// NewDay nations slowly forget the player's misdeeds
+func (r *Relations) NewDay() {
+	for n, v := range r.notoriety {
+		r.notoriety[n] = max(0, v-NotorietyDecay)
+	}
+}
@@ -0,0 +1,28 @@
+package faction
+
+import (
+	"pirate-wars/cmd/common"
+	"testing"
+)
+
+func TestRelations(t *testing.T) {
+	r := Init()
+	nation := common.Flags[1].Name
+	if !r.IsHostile(nation, common.PirateFlag.Name) {
+		t.Fatalf("every nation should be at war with the pirates")
+	}
+	if r.IsHostile(nation, nation) {
+		t.Fatalf("a nation can't be at war with itself")
+	}
+	if r.IsHostile(nation, common.PlayerFlag.Name) {
+		t.Fatalf("forts shouldn't fire on the player before they're wanted")
+	}
+	r.AddNotoriety(nation, WantedNotoriety)
+	if !r.IsHostile(nation, common.PlayerFlag.Name) {
+		t.Fatalf("forts should fire on a wanted player")
+	}
+	r.NewDay()
+	if r.IsWanted(nation) || r.GetNotoriety(nation) != WantedNotoriety-NotorietyDecay {
+		t.Fatalf("notoriety should be forgotten over time, got %d", r.GetNotoriety(nation))
+	}
+}
@@ -0,0 +1,316 @@
+package hideout
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math/rand"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/economy"
+	"pirate-wars/cmd/resources"
+	"pirate-wars/cmd/spatial"
+	"pirate-wars/cmd/town"
+	"pirate-wars/cmd/window"
+	"pirate-wars/cmd/world"
+
+	"go.uber.org/zap"
+)
+
+// FoundingCost gold it takes to build a hideout
+const FoundingCost = 500
+
+// FoundingCrew hands left ashore to build and hold the hideout
+const FoundingCrew = 10
+
+// ClaimedDistance tiles around a town in which the coast is already claimed
+const ClaimedDistance = 20
+
+// StashCapacity units of goods the hideout's stores hold
+const StashCapacity = 1000
+
+// DockRange tiles from the hideout within which ships are repaired at its dock
+const DockRange = 4
+
+// DockRepair hull and sail points repaired on each ship at the dock every day
+const DockRepair = 5
+
+// DefencesCost gold for each level of defences
+const DefencesCost = 300
+
+// MaxDefences levels of defences the hideout can be fortified with
+const MaxDefences = 3
+
+// DefenceTiles fort size, in town tiles, added with each level of defences
+const DefenceTiles = 2
+
+// TavernCost gold it takes to build a tavern
+const TavernCost = 200
+
+// HunterNotoriety notoriety with a nation at which it sends pirate-hunters after the hideout
+const HunterNotoriety = 50
+
+// HunterChance daily percentage chance of pirate-hunters finding the hideout
+const HunterChance = 10
+
+// HunterDistance tiles from the hideout at which pirate-hunters are first sighted
+const HunterDistance = 15
+
+// MaxHunters pirate-hunters sent after a single hideout at any one time
+const MaxHunters = 3
+
+// Hideout the player's own stronghold on the coast, with a stash of goods, a dock for the fleet,
+// and whatever the player has built there since
+type Hideout struct {
+	id        string
+	pos       common.Coordinates
+	ground    common.TerrainType
+	stash     economy.Goods
+	garrison  int
+	defences  int
+	fort      *town.Fort
+	tavern    bool
+	blink     bool
+	alternate bool
+}
+
+type Hideouts struct {
+	logger *zap.SugaredLogger
+	index  *spatial.Index
+	world  *world.MapView
+	list   []*Hideout
+}
+
+func (h *Hideout) GetID() string {
+	return h.id
+}
+
+func (h *Hideout) GetName() string {
+	return "Pirate hideout"
+}
+
+func (h *Hideout) GetType() string {
+	return "Hideout"
+}
+
+func (h *Hideout) GetFlag() string {
+	return common.PlayerFlag.Name
+}
+
+func (h *Hideout) GetPos() common.Coordinates {
+	return h.pos
+}
+
+func (h *Hideout) GetPreviousPos() common.Coordinates {
+	return h.pos
+}
+
+func (h *Hideout) GetViewableRange() window.Dimensions {
+	return window.Dimensions{Width: 20, Height: 20}
+}
+
+func (h *Hideout) Highlight(b bool) {
+	h.blink = b
+	h.alternate = b
+}
+
+func (h *Hideout) IsHighlighted() bool {
+	return h.blink
+}
+
+func (h *Hideout) GetColor() color.Color {
+	if h.blink {
+		if !h.alternate {
+			h.alternate = true
+			return color.RGBA{0, 0, 0, 0}
+		}
+	}
+	h.alternate = false
+	return color.RGBA{40, 40, 40, 255}
+}
+
+func (h *Hideout) GetTileImage() image.Image {
+	return resources.GetHideoutTile()
+}
+
+func (h *Hideout) GetDetails() string {
+	tavern := "none"
+	if h.tavern {
+		tavern = "built"
+	}
+	return fmt.Sprintf("Garrison: %d\nDefences: %d/%d (%d cannons)\nTavern: %s\nStash: %d/%d\n",
+		h.garrison, h.defences, MaxDefences, h.fort.GetCannons(), tavern, h.stash.Total(), StashCapacity)
+}
+
+// GetStash goods stored at the hideout
+func (h *Hideout) GetStash() economy.Goods {
+	return h.stash
+}
+
+// Store puts up to n units in the stash, returns how many there was room for
+func (h *Hideout) Store(c economy.CommodityType, n int) int {
+	stored := max(0, min(n, StashCapacity-h.stash.Total()))
+	h.stash[c] += stored
+	return stored
+}
+
+// Withdraw takes up to n units from the stash, returns how many there were
+func (h *Hideout) Withdraw(c economy.CommodityType, n int) int {
+	taken := max(0, min(n, h.stash[c]))
+	h.stash[c] -= taken
+	if h.stash[c] == 0 {
+		delete(h.stash, c)
+	}
+	return taken
+}
+
+func (h *Hideout) GetGarrison() int {
+	return h.garrison
+}
+
+func (h *Hideout) GetDefences() int {
+	return h.defences
+}
+
+// GetFort the battery guarding the hideout, without cannons until defences are built
+func (h *Hideout) GetFort() *town.Fort {
+	return h.fort
+}
+
+func (h *Hideout) HasTavern() bool {
+	return h.tavern
+}
+
+func (h *Hideout) BuildTavern() {
+	h.tavern = true
+}
+
+// GetDefencesCost gold the next level of defences costs
+func (h *Hideout) GetDefencesCost() int {
+	return (h.defences + 1) * DefencesCost
+}
+
+// Fortify builds the next level of defences, extending the battery without making good the
+// damage it's taken
+func (hs *Hideouts) Fortify(h *Hideout) {
+	h.defences++
+	hs.index.Remove(h.fort.GetID())
+	h.fort.Extend(h.defences * DefenceTiles)
+	hs.index.Insert(h.fort, spatial.KindFort)
+}
+
+// Bombard the battery takes the damage while it has cannons left, then the garrison suffers
+func (h *Hideout) Bombard(damage int, casualties int) {
+	if h.fort.GetCannons() > 0 {
+		h.fort.Bombard(damage, casualties)
+		return
+	}
+	h.garrison = max(0, h.garrison-casualties)
+}
+
+// IsSacked whether the garrison has been wiped out
+func (h *Hideout) IsSacked() bool {
+	return h.garrison == 0
+}
+
+// InDock whether c lies close enough to the hideout to be repaired at its dock
+func (h *Hideout) InDock(c common.Coordinates) bool {
+	return common.Distance(h.pos, c) <= DockRange
+}
+
+// Covers whether c is the hideout's tile
+func (h *Hideout) Covers(c common.Coordinates) bool {
+	return common.CoordsMatch(h.pos, c)
+}
+
+// HunterSpawn a spot out at sea from which pirate-hunters close in on the hideout
+func (hs *Hideouts) HunterSpawn(h *Hideout) (common.Coordinates, bool) {
+	for i := 0; i < 50; i++ {
+		d := common.Directions[rand.Intn(len(common.Directions))]
+		c := common.Coordinates{X: h.pos.X + d.X*HunterDistance + rand.Intn(5) - 2, Y: h.pos.Y + d.Y*HunterDistance + rand.Intn(5) - 2}
+		if common.Inbounds(c) && hs.world.IsPassableByBoat(c) {
+			return c, true
+		}
+	}
+	return common.Coordinates{}, false
+}
+
+// CanFound whether c is coast nobody has claimed yet
+func (hs *Hideouts) CanFound(c common.Coordinates, towns []town.Town) bool {
+	p := hs.world.GetPositionType(c)
+	if (p != common.TerrainTypeLowland && p != common.TerrainTypeBeach) || !hs.world.IsAdjacentToWater(c) {
+		return false
+	}
+	for _, t := range towns {
+		if common.Distance(c, t.GetPos()) < ClaimedDistance {
+			return false
+		}
+	}
+	for _, h := range hs.list {
+		if common.Distance(c, h.pos) < ClaimedDistance {
+			return false
+		}
+	}
+	return true
+}
+
+// Found builds a hideout on the coast at c, garrisoned by the hands left ashore
+func (hs *Hideouts) Found(c common.Coordinates, garrison int) *Hideout {
+	h := &Hideout{
+		id:       common.GenID(c),
+		pos:      c,
+		ground:   hs.world.GetPositionType(c),
+		stash:    economy.Goods{},
+		garrison: garrison,
+	}
+	h.fort = town.CreateFort(h.id, c, 0)
+	hs.world.SetPositionType(c, common.TerrainTypeHideout)
+	hs.logger.Infof("[%v] Hideout founded at %v", h.id, c)
+	hs.list = append(hs.list, h)
+	hs.index.Insert(h, spatial.KindHideout)
+	return h
+}
+
+// Remove the hideout is razed, its coast returns to the wild
+func (hs *Hideouts) Remove(h *Hideout) {
+	for i, o := range hs.list {
+		if o == h {
+			hs.list = append(hs.list[:i], hs.list[i+1:]...)
+			break
+		}
+	}
+	hs.world.SetPositionType(h.pos, h.ground)
+	hs.index.Remove(h.fort.GetID())
+	hs.index.Remove(h.id)
+	hs.logger.Infof("[%v] Hideout razed at %v", h.id, h.pos)
+}
+
+func (hs *Hideouts) GetList() []*Hideout {
+	return hs.list
+}
+
+// Nearest the hideout closest to c, if there are any left
+func (hs *Hideouts) Nearest(c common.Coordinates) (*Hideout, bool) {
+	var nearest *Hideout
+	for _, h := range hs.list {
+		if nearest == nil || common.Distance(c, h.pos) < common.Distance(c, nearest.pos) {
+			nearest = h
+		}
+	}
+	return nearest, nearest != nil
+}
+
+// NewDay the battery is repaired and fresh gunners join it
+func (hs *Hideouts) NewDay() {
+	for _, h := range hs.list {
+		h.fort.NewDay()
+	}
+}
+
+func Init(world *world.MapView, index *spatial.Index, logger *zap.SugaredLogger) *Hideouts {
+	return &Hideouts{
+		logger: logger,
+		index:  index,
+		world:  world,
+		list:   []*Hideout{},
+	}
+}
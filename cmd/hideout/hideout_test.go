@@ -0,0 +1,73 @@
+package hideout
+
+import (
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/economy"
+	"pirate-wars/cmd/spatial"
+	"pirate-wars/cmd/town"
+	"pirate-wars/cmd/world"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"go.uber.org/zap"
+)
+
+func TestHideout(t *testing.T) {
+	test.NewApp()
+	test.NewWindow(nil)
+	logger := zap.NewNop().Sugar()
+	w := world.Init(logger)
+	index := spatial.New()
+	ts := town.Init(w, index, logger)
+	hs := Init(w, index, logger)
+
+	for _, c := range ts.GetTowns()[0].GetTiles() {
+		if hs.CanFound(c, ts.GetTowns()) {
+			t.Fatalf("coast at %v belongs to a town", c)
+		}
+	}
+	var site common.Coordinates
+	found := false
+	for x := 0; x < common.WorldCols && !found; x++ {
+		for y := 0; y < common.WorldRows && !found; y++ {
+			site = common.Coordinates{X: x, Y: y}
+			found = hs.CanFound(site, ts.GetTowns())
+		}
+	}
+	if !found {
+		t.Skip("no unclaimed coast on this map")
+	}
+	h := hs.Found(site, FoundingCrew)
+	if w.GetPositionType(site) != common.TerrainTypeHideout || hs.CanFound(site, ts.GetTowns()) {
+		t.Fatalf("hideout should claim the coast at %v", site)
+	}
+
+	c := economy.CommodityType(economy.CommodityRum)
+	if h.Store(c, StashCapacity+10) != StashCapacity || h.Withdraw(c, 10) != 10 || h.GetStash()[c] != StashCapacity-10 {
+		t.Fatalf("stash should hold up to %d units, holds %d", StashCapacity, h.GetStash()[c])
+	}
+
+	hs.Fortify(h)
+	cannons := h.GetFort().GetCannons()
+	if cannons != DefenceTiles*town.FortCannonsPerTile {
+		t.Fatalf("first level of defences should mount %d cannons, got %d", DefenceTiles*town.FortCannonsPerTile, cannons)
+	}
+	h.GetFort().Bombard(town.FortWallsPerCannon, 0)
+	hs.Fortify(h)
+	cannons = h.GetFort().GetCannons()
+	if cannons != 2*DefenceTiles*town.FortCannonsPerTile-1 {
+		t.Fatalf("fortifying shouldn't remount the cannon dismounted, mounts %d", cannons)
+	}
+	h.Bombard(cannons*town.FortWallsPerCannon, FoundingCrew)
+	if h.IsSacked() || h.GetFort().GetCannons() != 0 {
+		t.Fatalf("the battery should take the first bombardment")
+	}
+	h.Bombard(0, FoundingCrew)
+	if !h.IsSacked() {
+		t.Fatalf("with the battery silenced the garrison should fall")
+	}
+	hs.Remove(h)
+	if w.GetPositionType(site) == common.TerrainTypeHideout || len(hs.GetList()) != 0 {
+		t.Fatalf("sacked hideout should be razed")
+	}
+}
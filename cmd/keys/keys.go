@@ -0,0 +1,48 @@
+package keys
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+)
+
+// Bindings the keys bound to each action, by the name of the key map and then of the action
+type Bindings map[string]map[string][]string
+
+// Write saves the bindings as JSON to path, replacing the file
+func Write(path string, b Bindings) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Read loads bindings saved by Write
+func Read(path string) (Bindings, error) {
+	var b Bindings
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return b, err
+	}
+	err = json.Unmarshal(data, &b)
+	return b, err
+}
+
+// Conflicts keys bound to more than one action of the same key map, with the actions bound to each
+func Conflicts(actions map[string][]string) map[string][]string {
+	bound := map[string][]string{}
+	for action, keys := range actions {
+		for _, k := range keys {
+			bound[k] = append(bound[k], action)
+		}
+	}
+	conflicts := map[string][]string{}
+	for k, a := range bound {
+		if len(a) > 1 {
+			sort.Strings(a)
+			conflicts[k] = a
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,32 @@
+package keys
+
+import (
+	"path/filepath"
+	"pirate-wars/cmd/common"
+	"testing"
+)
+
+func TestWriteRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), common.KeysFile)
+	b := Bindings{"sailing": {"minimap": {"M"}, "examine": {"X", "Return"}}}
+	if err := Write(path, b); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	l, err := Read(path)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if len(l["sailing"]) != 2 || l["sailing"]["minimap"][0] != "M" || len(l["sailing"]["examine"]) != 2 {
+		t.Fatalf("loaded bindings %+v don't match saved %+v", l, b)
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	c := Conflicts(map[string][]string{"examine": {"X"}, "down & left": {"Z", "X"}, "minimap": {"M"}})
+	if len(c) != 1 || len(c["X"]) != 2 || c["X"][0] != "down & left" || c["X"][1] != "examine" {
+		t.Fatalf("expected X to be bound to both examine and down & left, got %+v", c)
+	}
+	if c := Conflicts(map[string][]string{"examine": {"X"}, "minimap": {"M"}}); len(c) != 0 {
+		t.Fatalf("expected no conflicts, got %+v", c)
+	}
+}
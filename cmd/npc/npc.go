@@ -5,41 +5,110 @@ import (
 	"image"
 	"image/color"
 	"math/rand"
+	"pirate-wars/cmd/combat"
 	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/economy"
 	"pirate-wars/cmd/entities"
 	"pirate-wars/cmd/resources"
+	"pirate-wars/cmd/ship"
+	"pirate-wars/cmd/spatial"
 	"pirate-wars/cmd/town"
 	"pirate-wars/cmd/window"
 	"pirate-wars/cmd/world"
-	"sort"
+	"pirate-wars/cmd/wreck"
 
 	"go.uber.org/zap"
 )
 
-// ChanceToMove Percentage chance an NPC will calculate movement per tick
-const ChanceToMove = 50
 const GoalTypeTrade = 1
+const GoalTypeFollow = 2
+const GoalTypeGuard = 3
+const GoalTypeGoTo = 4
+const GoalTypeHunt = 5
+const GoalTypeDock = 6
+
+const OwnerNone = 0
+const OwnerPlayer = 1
+
+// FollowDistance how close a following ship keeps to its station in formation
+const FollowDistance = 1
+
+// ArrivalCost heatmap cost below which an NPC is considered to have reached a town
+const ArrivalCost = 3
+
+// DetailMargin tiles beyond the player's viewport in which NPCs are simulated tile by tile,
+// further out they only advance along a pre-planned route.
+const DetailMargin = 10
+
+// ReplanTicks ticks a coarsely simulated NPC whose route leads nowhere waits before planning again
+const ReplanTicks = 20
+
+// SinkingTicks ticks a sunk ship stays on the map before it's gone for good
+const SinkingTicks = 20
 
 type Agenda struct {
 	goal        int
 	tradeTarget int
 	tadeRoute   []town.Town
+	leader      Leader
+	formation   common.Coordinates
+}
+
+// formationOffsets where fleet ships keep station around their leader, in order of appointment
+var formationOffsets = []common.Coordinates{
+	{X: -2, Y: 2}, {X: 2, Y: 2}, {X: 0, Y: 3}, {X: -3, Y: 0},
+	{X: 3, Y: 0}, {X: -2, Y: -2}, {X: 2, Y: -2}, {X: 0, Y: -3},
+}
+
+// FormationOffset station kept by the n-th ship of a fleet
+func FormationOffset(n int) common.Coordinates {
+	o := formationOffsets[n%len(formationOffsets)]
+	ring := n/len(formationOffsets) + 1
+	return common.Coordinates{X: o.X * ring, Y: o.Y * ring}
+}
+
+// Leader anything a ship can follow
+type Leader interface {
+	GetPos() common.Coordinates
 }
 
 type Npc struct {
-	id     string
-	name   string
-	eType  string
-	flag   string
-	ship   common.ShipType
-	logger *zap.SugaredLogger
-	avatar entities.Avatar
-	agenda Agenda
+	id       string
+	name     string
+	eType    string
+	flag     string
+	shipType common.ShipType
+	ship     *ship.Ship
+	logger   *zap.SugaredLogger
+	avatar   entities.Avatar
+	agenda   Agenda
+	owner    int
+	// provoked by the player, will return fire
+	hostile bool
+	sunkFor int
+	// sent to the bottom by the player or the player's fleet, rather than by forts or storms
+	sunkByPlayer bool
+	// tiles travelled towards the next move, or along the route when simulated coarsely
+	progress float64
+	// coarse simulation state, only used while outside the detail region
+	coarse bool
+	route  []common.Coordinates
+	// ticks until a route that led nowhere is planned again
+	replanIn int
+}
+
+// Target anything NPCs can fire upon
+type Target interface {
+	GetPos() common.Coordinates
+	GetShip() *ship.Ship
 }
 
 type Npcs struct {
 	logger *zap.SugaredLogger
-	list   []Npc
+	list   []*Npc
+	index  *spatial.Index
+	world  *world.MapView
+	wrecks *wreck.Wrecks
 }
 
 func (n *Npc) GetName() string {
@@ -69,9 +138,160 @@ func (n *Npc) GetID() string {
 }
 
 func (n *Npc) GetTileImage() image.Image {
+	if n.IsSinking() {
+		return resources.GetWreckTile(n.shipType)
+	}
 	return n.avatar.GetTileImage()
 }
 
+func (n *Npc) GetShip() *ship.Ship {
+	return n.ship
+}
+
+func (n *Npc) IsSinking() bool {
+	return n.ship.IsSunk()
+}
+
+// IsSunkByPlayer whether the ship went down under the guns of the player or the player's fleet
+func (n *Npc) IsSunkByPlayer() bool {
+	return n.IsSinking() && n.sunkByPlayer
+}
+
+// TakePlayerFire the ship has come under the guns of the player or the player's fleet, who are
+// credited with sinking her if she goes down
+func (n *Npc) TakePlayerFire() {
+	n.hostile = true
+	n.sunkByPlayer = n.IsSinking()
+}
+
+func (n *Npc) IsHostile() bool {
+	return n.hostile
+}
+
+// Provoke makes the NPC return fire on the player
+func (n *Npc) Provoke() {
+	n.hostile = true
+}
+
+func (n *Npc) IsOwnedByPlayer() bool {
+	return n.owner == OwnerPlayer
+}
+
+// Capture the ship is taken as a prize, it hoists the new flag, gets a newly appointed captain and
+// abandons its old agenda to follow the leader in formation
+func (n *Npc) Capture(leader Leader, flag common.Flag, formation common.Coordinates) {
+	n.logger.Infof("[%v] NPC captured at %v", n.GetID(), n.GetPos())
+	n.owner = OwnerPlayer
+	n.hostile = false
+	n.name = common.GenerateCaptainName()
+	n.flag = flag.Name
+	n.shipType = flag.Ship
+	n.avatar.SetTileImage(resources.GetShipTile(flag.Ship))
+	n.avatar.SetColor(flag.Color)
+	n.Follow(leader, formation)
+}
+
+// Follow orders the ship to keep station at the formation offset from the leader
+func (n *Npc) Follow(leader Leader, formation common.Coordinates) {
+	n.agenda = Agenda{goal: GoalTypeFollow, leader: leader, formation: formation}
+	n.coarse = false
+	n.route = nil
+}
+
+// Guard orders the ship to hold its position, firing upon hostile ships that come in range
+func (n *Npc) Guard() {
+	n.agenda = Agenda{goal: GoalTypeGuard, leader: n.agenda.leader, formation: n.agenda.formation}
+	n.coarse = false
+	n.route = nil
+}
+
+// GoTo orders the ship to sail to a town and drop anchor there
+func (n *Npc) GoTo(t town.Town) {
+	n.agenda = Agenda{goal: GoalTypeGoTo, tadeRoute: []town.Town{t}, leader: n.agenda.leader, formation: n.agenda.formation}
+	n.coarse = false
+	n.route = nil
+}
+
+// Dock orders the ship to sail to the hideout and moor at its dock
+func (n *Npc) Dock(hideout Leader) {
+	n.agenda = Agenda{goal: GoalTypeDock, leader: hideout, formation: n.agenda.formation}
+	n.coarse = false
+	n.route = nil
+}
+
+// GetLeader the ship followed when ordered to, if any
+func (n *Npc) GetLeader() Leader {
+	return n.agenda.leader
+}
+
+func (n *Npc) GetFormation() common.Coordinates {
+	return n.agenda.formation
+}
+
+// GetOrders a short description of what the ship is up to
+func (n *Npc) GetOrders() string {
+	switch n.agenda.goal {
+	case GoalTypeFollow:
+		return "Follow"
+	case GoalTypeGuard:
+		return "Guard"
+	case GoalTypeGoTo:
+		return fmt.Sprintf("Sail to %s", n.targetTown().GetName())
+	case GoalTypeHunt:
+		return "Hunt pirates"
+	case GoalTypeDock:
+		return "Dock at hideout"
+	}
+	return "Trade"
+}
+
+// IsHunter whether the ship was sent to hunt down the player's hideout
+func (n *Npc) IsHunter() bool {
+	return n.agenda.goal == GoalTypeHunt
+}
+
+// IsInPort whether the ship lies at anchor by the town it was sent to
+func (n *Npc) IsInPort() bool {
+	return n.agenda.goal == GoalTypeGoTo && n.hasArrived()
+}
+
+// Mutiny the crew takes the ship, it runs up the flag, elects a captain from among the mutineers
+// and turns on its former fleet
+func (n *Npc) Mutiny(flag common.Flag) {
+	n.logger.Infof("[%v] NPC mutinied at %v", n.GetID(), n.GetPos())
+	n.owner = OwnerNone
+	n.hostile = true
+	n.name = common.GenerateCaptainName()
+	n.flag = flag.Name
+	n.shipType = flag.Ship
+	n.avatar.SetTileImage(resources.GetShipTile(flag.Ship))
+	n.avatar.SetColor(flag.Color)
+	n.Guard()
+}
+
+func (n *Npc) hasArrived() bool {
+	return n.targetTown().HeatMap.GetCost(n.GetPos()) < ArrivalCost
+}
+
+// Release the ship is let go after being boarded
+func (n *Npc) Release() {
+	n.hostile = false
+}
+
+func (n *Npc) GetDetails() string {
+	s := n.ship
+	details := fmt.Sprintf("Ship: %s\nHull: %d/%d\nCannons: %d\nCrew: %d\nSpeed: %d\n",
+		s.GetClass().Name, s.GetHull(), s.GetClass().Hull, s.GetClass().Cannons, s.GetCrew(), s.GetClass().Speed)
+	if n.IsSinking() {
+		details += "Sinking!\n"
+	} else if n.IsOwnedByPlayer() {
+		details += "Prize\n"
+	} else if n.hostile {
+		details += "Hostile\n"
+	}
+	return details
+}
+
 func (n *Npc) GetViewableRange() window.Dimensions {
 	return window.Dimensions{Width: 20, Height: 20}
 }
@@ -88,7 +308,7 @@ func (n *Npc) GetColor() color.Color {
 	return n.avatar.GetColor()
 }
 
-func (ns *Npcs) ForEach(fn func(n Npc)) {
+func (ns *Npcs) ForEach(fn func(n *Npc)) {
 	for _, n := range ns.list {
 		fn(n)
 	}
@@ -123,14 +343,18 @@ func (ns *Npcs) Create(towns *town.Towns, world *world.MapView) {
 
 	// c := entities.ColorPossibilities[rand.Intn(len(entities.ColorPossibilities)-1)]
 	flag := common.GetRandomFlag()
+	s := ship.Create(ship.RandomClass())
+	s.LoadCargo(economy.RandomCommodity(), rand.Intn(s.GetClass().Cargo+1))
+	s.AddGold(rand.Intn(s.GetClass().Cargo * 2))
 
 	npc := Npc{
-		eType:  "NPC",
-		logger: ns.logger,
-		name:   common.GenerateCaptainName(),
-		flag:   flag.Name,
-		ship:   flag.Ship,
-		avatar: entities.CreateAvatar(pos, resources.GetShipTile(flag.Ship), flag.Color),
+		eType:    "NPC",
+		logger:   ns.logger,
+		name:     common.GenerateCaptainName(),
+		flag:     flag.Name,
+		shipType: flag.Ship,
+		ship:     s,
+		avatar:   entities.CreateAvatar(pos, resources.GetShipTile(flag.Ship), flag.Color),
 		agenda: Agenda{
 			goal:        GoalTypeTrade,
 			tradeTarget: 0,
@@ -138,12 +362,42 @@ func (ns *Npcs) Create(towns *town.Towns, world *world.MapView) {
 		},
 	}
 	ns.logger.Infof("[%v] NPC created at %d, %d", npc.id, pos.X, pos.Y)
-	ns.list = append(ns.list, npc)
+	ns.list = append(ns.list, &npc)
+	ns.index.Insert(&npc, spatial.KindNpc)
+	npc.avatar.Track(ns.index)
 }
 
-func Init(towns *town.Towns, world *world.MapView, logger *zap.SugaredLogger) *Npcs {
+// CreateHunter a warship of the nation sent after the player's hideout, it closes in on the target
+// spoiling for a fight
+func (ns *Npcs) CreateHunter(pos common.Coordinates, flag common.Flag, target Leader) *Npc {
+	class := ship.ClassType(ship.ClassFrigate)
+	if rand.Intn(2) == 0 {
+		class = ship.ClassManOfWar
+	}
+	npc := Npc{
+		eType:    "Pirate hunter",
+		logger:   ns.logger,
+		name:     common.GenerateCaptainName(),
+		flag:     flag.Name,
+		shipType: flag.Ship,
+		ship:     ship.Create(class),
+		avatar:   entities.CreateAvatar(pos, resources.GetShipTile(flag.Ship), flag.Color),
+		agenda:   Agenda{goal: GoalTypeHunt, leader: target, formation: FormationOffset(rand.Intn(len(formationOffsets)))},
+		hostile:  true,
+	}
+	ns.logger.Infof("[%v] Pirate hunter created at %d, %d", npc.id, pos.X, pos.Y)
+	ns.list = append(ns.list, &npc)
+	ns.index.Insert(&npc, spatial.KindNpc)
+	npc.avatar.Track(ns.index)
+	return &npc
+}
+
+func Init(towns *town.Towns, world *world.MapView, index *spatial.Index, wrecks *wreck.Wrecks, logger *zap.SugaredLogger) *Npcs {
 	ns := Npcs{
 		logger: logger,
+		index:  index,
+		world:  world,
+		wrecks: wrecks,
 	}
 	for i := 0; i < common.TotalNpcs; i++ {
 		ns.Create(towns, world)
@@ -152,73 +406,356 @@ func Init(towns *town.Towns, world *world.MapView, logger *zap.SugaredLogger) *N
 	return &ns
 }
 
-func (ns *Npcs) CalcMovements() {
-	ns.logger.Infof("Calculating NPC movements: %d", len(ns.list))
-	for i := range ns.list {
-		if rand.Intn(100) > ChanceToMove {
+// CalcMovements advances every NPC by one tick. NPCs near focus (usually the player) move tile by
+// tile, all others progress along their route by distance over time.
+func (ns *Npcs) CalcMovements(focus common.Coordinates) {
+	ns.calcMovements(detailRegion(focus))
+}
+
+func (ns *Npcs) calcMovements(detail window.Region) {
+	ns.logger.Debugf("Calculating NPC movements: %d", len(ns.list))
+	ns.keepStations()
+	remaining := ns.list[:0]
+	for _, npc := range ns.list {
+		npc.ship.Tick()
+		if npc.IsSinking() {
+			// wrecks don't move, and eventually disappear beneath the waves
+			npc.sunkFor++
+			if npc.sunkFor > SinkingTicks {
+				ns.logger.Infof("[%v] NPC sank at %v", npc.GetID(), npc.GetPos())
+				ns.index.Remove(npc.GetID())
+				s := npc.ship
+				ns.wrecks.Create(npc.GetPos(), fmt.Sprintf("%s's %s", npc.name, s.GetClass().Name), npc.shipType, s.GetHold(), s.GetGold())
+				continue
+			}
+		} else if npc.agenda.goal == GoalTypeFollow || npc.agenda.goal == GoalTypeHunt || npc.agenda.goal == GoalTypeDock {
+			ns.stepFollow(npc)
+		} else if npc.agenda.goal == GoalTypeGuard {
+			// holds its position
+		} else if detail.IsPositionWithin(npc.GetPos()) {
+			if npc.coarse {
+				npc.coarse = false
+				npc.route = nil
+				npc.progress = 0
+			}
+			ns.stepDetailed(npc)
+		} else {
+			if !npc.coarse {
+				npc.coarse = true
+				npc.planRoute()
+			}
+			ns.stepCoarse(npc)
+		}
+		remaining = append(remaining, npc)
+	}
+	ns.list = remaining
+}
+
+// ReturnFire provoked NPCs near the target fire at it once they're loaded and in range
+func (ns *Npcs) ReturnFire(target Target) []combat.Shot {
+	shots := []combat.Shot{}
+	for _, item := range ns.index.QueryRadius(target.GetPos(), ship.MaxRange(), spatial.KindNpc) {
+		n := item.(*Npc)
+		d := common.Distance(n.GetPos(), target.GetPos())
+		if !n.hostile || n.IsSinking() || !n.ship.IsLoaded() || !combat.InRange(n.ship, d) {
+			continue
+		}
+		shot := combat.Fire(n.ship, target.GetShip(), target.GetPos(), d)
+		ns.logger.Infof("[%v] NPC fires at %v: %+v", n.GetID(), target.GetPos(), shot)
+		shots = append(shots, shot)
+	}
+	return shots
+}
+
+// detailRegion the area around focus in which NPCs get the full per-tile simulation
+func detailRegion(focus common.Coordinates) window.Region {
+	vp := window.GetViewportRegion(focus)
+	return window.Region{
+		X:    vp.X - DetailMargin,
+		Y:    vp.Y - DetailMargin,
+		Cols: vp.Cols + DetailMargin*2,
+		Rows: vp.Rows + DetailMargin*2,
+	}
+}
+
+func (n *Npc) targetTown() *town.Town {
+	return &n.agenda.tadeRoute[n.agenda.tradeTarget]
+}
+
+// GetDestination the town a trader is bound for
+func (n *Npc) GetDestination() (*town.Town, bool) {
+	if n.agenda.goal != GoalTypeTrade {
+		return nil, false
+	}
+	return n.targetTown(), true
+}
+
+// switchTradeTarget flips the trade route once the current target town has been reached, after
+// trading there
+func (ns *Npcs) switchTradeTarget(npc *Npc) {
+	oldTown := npc.targetTown()
+	npc.trade(oldTown)
+	npc.agenda.tradeTarget = npc.agenda.tradeTarget ^ 1
+	ns.logger.Info(fmt.Sprintf("[%v] NPC movement trade route switch town %v to town %v", npc.id, oldTown.GetPos(), npc.targetTown().GetPos()))
+}
+
+// trade sells the hold at the town's market and loads up with whatever it has to spare, moving
+// stock from the towns producing it to the towns in need of it
+func (n *Npc) trade(t *town.Town) {
+	if t.IsGhostTown() {
+		return
+	}
+	m := t.GetMarket()
+	s := n.ship
+	for c, units := range s.GetHold() {
+		price := m.GetSellPrice(c)
+		sold := m.Sell(c, units)
+		s.UnloadCargo(c, sold)
+		s.AddGold(sold * price)
+	}
+	for _, c := range economy.Commodities() {
+		if !m.IsSurplus(c) {
 			continue
 		}
+		price := m.GetPrice(c)
+		units := min(m.GetStock(c)-economy.TargetStock, s.GetClass().Cargo-s.GetCargo(), s.GetGold()/price)
+		bought := s.LoadCargo(c, m.Buy(c, units))
+		s.SpendGold(bought * price)
+	}
+}
 
-		npc := &ns.list[i]
-		targetTown := &npc.agenda.tadeRoute[npc.agenda.tradeTarget]
+// nextStep finds the cheapest neighbouring position on the target town's heatmap
+func nextStep(pos common.Coordinates, targetTown *town.Town) town.DirectionCost {
+	opts := []town.DirectionCost{}
+	for _, dir := range common.Directions {
+		n := common.AddDirection(pos, dir)
+		if !common.Inbounds(n) {
+			// don't check out of bounds
+			continue
+		}
+		opts = append(opts, town.DirectionCost{Pos: n, Cost: targetTown.HeatMap.GetCost(n)})
+	}
+	return town.DecideDirection(opts, targetTown.GetPos())
+}
 
-		// if we're already at our destination, flip our trade route
-		if targetTown.HeatMap.GetCost(npc.avatar.GetPos()) < 3 {
-			oldTown := npc.agenda.tadeRoute[npc.agenda.tradeTarget]
-			npc.agenda.tradeTarget = npc.agenda.tradeTarget ^ 1
-			targetTown = &npc.agenda.tadeRoute[npc.agenda.tradeTarget]
-			ns.logger.Info(fmt.Sprintf("[%v] NPC movement trade route switch town %v to town %v", npc.id, oldTown.GetPos(), targetTown.GetPos()))
+// planRoute follows the target town's heatmap downhill from the current position, the resulting
+// path is what the coarse simulation travels along.
+func (n *Npc) planRoute() {
+	targetTown := n.targetTown()
+	pos := n.GetPos()
+	n.progress = 0
+	n.replanIn = 0
+	n.route = []common.Coordinates{pos}
+	for targetTown.HeatMap.GetCost(pos) >= ArrivalCost {
+		pick := nextStep(pos, targetTown)
+		if pick.Cost >= targetTown.HeatMap.GetCost(pos) {
+			// stuck, stop planning here
+			break
 		}
+		pos = pick.Pos
+		n.route = append(n.route, pos)
+	}
+}
 
-		// find next move by cost on heatmap
-		opts := []town.DirectionCost{}
-		for _, dir := range common.Directions {
-			n := common.AddDirection(npc.GetPos(), dir)
-			if !common.Inbounds(n) {
-				// don't check out of bounds
-				continue
-			}
-			//t.Logger.Debug(fmt.Sprintf("New heatmap coordinates check [%v][%v]", newX, newY))
-			//t.Logger.Debug(fmt.Sprintf("Npc at %v, %v - checking square %v, %v cost:%v [lowest cost: %v]", newPosition.X, newPosition.Y, newX, newY, town.HeatMap[newX][newY], lowestCost)
-			opts = append(opts, town.DirectionCost{Pos: n, Cost: targetTown.HeatMap.GetCost(n)})
+func (ns *Npcs) stepCoarse(npc *Npc) {
+	if npc.replanIn > 0 {
+		npc.replanIn--
+		return
+	}
+	npc.progress += npc.ship.GetSpeed()
+	idx := int(npc.progress)
+	if idx < len(npc.route)-1 {
+		npc.SetPos(npc.route[idx])
+		return
+	}
+	npc.SetPos(npc.route[len(npc.route)-1])
+	if npc.agenda.goal == GoalTypeTrade && npc.hasArrived() {
+		ns.switchTradeTarget(npc)
+	}
+	npc.planRoute()
+	if len(npc.route) < 2 {
+		// stuck where it is, don't search for a way out again every tick
+		npc.replanIn = ReplanTicks
+	}
+}
+
+// underway the ship makes way at its speed, returns true once it has covered a whole tile
+func (n *Npc) underway() bool {
+	n.progress += n.ship.GetSpeed()
+	if n.progress < 1 {
+		return false
+	}
+	n.progress -= 1
+	return true
+}
+
+// stepFollow moves towards its station in formation around the leader
+func (ns *Npcs) stepFollow(npc *Npc) {
+	leader := common.AddDirection(npc.agenda.leader.GetPos(), npc.agenda.formation)
+	pos := npc.GetPos()
+	if common.Distance(pos, leader) < FollowDistance || !npc.underway() {
+		return
+	}
+	best := pos
+	for _, dir := range common.Directions {
+		n := common.AddDirection(pos, dir)
+		if !common.Inbounds(n) || !ns.world.IsPassableByBoat(n) {
+			continue
+		}
+		if common.Distance(n, leader) < common.Distance(best, leader) {
+			best = n
+		}
+	}
+	npc.SetPos(best)
+}
+
+func (ns *Npcs) stepDetailed(npc *Npc) {
+	if !npc.underway() {
+		return
+	}
+
+	// if we're already at our destination, flip our trade route or drop anchor
+	if npc.hasArrived() {
+		if npc.agenda.goal != GoalTypeTrade {
+			return
 		}
+		ns.switchTradeTarget(npc)
+	}
+	targetTown := npc.targetTown()
 
-		pick := town.DecideDirection(opts, targetTown.GetPos())
-		target := pick.Pos
-		cost := pick.Cost
-		npcpos := npc.GetPos()
+	// find next move by cost on heatmap
+	pick := nextStep(npc.GetPos(), targetTown)
+	target := pick.Pos
+	cost := pick.Cost
+	npcpos := npc.GetPos()
 
-		if target.X == npcpos.X && target.Y == npcpos.Y {
-			ns.logger.Debug(fmt.Sprintf("[%v] NPC stuck at %+v! Travelling to town at %v (cost %v)", npc.id, npcpos, targetTown.GetPos(), cost))
-		} else {
-			ns.logger.Info(fmt.Sprintf("[%v] NPC moving from %v to %v (cost %v) (color: %v)", npc.id, npcpos, target, cost, npc.GetColor()))
-			if !common.IsPositionAdjacent(npcpos, target) {
-				ns.logger.Debug(fmt.Sprintf("[%v] NPC warp! from %v to %v", npc.id, npcpos, target))
-			}
-			npc.SetPos(target)
+	if target.X == npcpos.X && target.Y == npcpos.Y {
+		ns.logger.Debug(fmt.Sprintf("[%v] NPC stuck at %+v! Travelling to town at %v (cost %v)", npc.id, npcpos, targetTown.GetPos(), cost))
+	} else {
+		ns.logger.Debug(fmt.Sprintf("[%v] NPC moving from %v to %v (cost %v) (color: %v)", npc.id, npcpos, target, cost, npc.GetColor()))
+		if !common.IsPositionAdjacent(npcpos, target) {
+			ns.logger.Debug(fmt.Sprintf("[%v] NPC warp! from %v to %v", npc.id, npcpos, target))
+		}
+		npc.SetPos(target)
+		if ns.world.GetPositionType(target) == common.TerrainTypeShallowWater {
+			npc.ship.Ground()
 		}
 	}
 }
 
-func (ns *Npcs) GetList() []Npc {
-	return ns.list
+// NewDay the carpenters aboard every ship patch up the day's damage
+func (ns *Npcs) NewDay() {
+	for _, npc := range ns.list {
+		npc.ship.RepairAtSea()
+	}
 }
 
-func (ns *Npcs) GetVisible(c common.Coordinates, vr window.Dimensions) Npcs {
-	vp := window.GetViewportRegion(c)
-	viewable := map[int]Npc{}
-	keys := []int{}
+// Replan traders simulated coarsely plan their routes afresh, e.g. to take a newly dug channel
+func (ns *Npcs) Replan() {
 	for _, npc := range ns.list {
-		p := npc.GetPos()
-		if vp.IsPositionWithin(p) {
-			keys = append(keys, p.X)
-			viewable[p.X] = npc
+		if npc.coarse && npc.agenda.goal == GoalTypeTrade && !npc.IsSinking() {
+			npc.planRoute()
 		}
 	}
-	sorted := Npcs{}
-	sort.Ints(keys)
-	for _, key := range keys {
-		sorted.list = append(sorted.list, viewable[key])
+}
+
+// Remove the ship leaves the seas for good, e.g. sold to a shipyard
+func (ns *Npcs) Remove(n *Npc) {
+	for i, o := range ns.list {
+		if o == n {
+			ns.list = append(ns.list[:i], ns.list[i+1:]...)
+			break
+		}
+	}
+	ns.index.Remove(n.GetID())
+}
+
+func (ns *Npcs) GetList() []*Npc {
+	return ns.list
+}
+
+// GetFleet ships owned by the player
+func (ns *Npcs) GetFleet() []*Npc {
+	fleet := []*Npc{}
+	for _, n := range ns.list {
+		if n.IsOwnedByPlayer() {
+			fleet = append(fleet, n)
+		}
+	}
+	return fleet
+}
+
+// GetHunters pirate-hunters still afloat closing in on the target
+func (ns *Npcs) GetHunters(target Leader) []*Npc {
+	hunters := []*Npc{}
+	for _, n := range ns.list {
+		if n.IsHunter() && !n.IsSinking() && n.agenda.leader == target {
+			hunters = append(hunters, n)
+		}
+	}
+	return hunters
+}
+
+// Retarget pirate-hunters closing in on a target that's gone turn on another
+func (ns *Npcs) Retarget(from Leader, to Leader) {
+	for _, n := range ns.GetHunters(from) {
+		n.agenda.leader = to
+	}
+}
+
+// FleetStation the station in formation of a ship of the player's fleet, or of a prize about to
+// join it, by its position in the fleet
+func (ns *Npcs) FleetStation(n *Npc) common.Coordinates {
+	i := 0
+	for _, f := range ns.list {
+		if f == n {
+			break
+		}
+		if f.IsOwnedByPlayer() {
+			i++
+		}
+	}
+	return FormationOffset(i)
+}
+
+// keepStations moves the ships of the fleet following the player to their stations as the fleet
+// changes, so no two ever share one
+func (ns *Npcs) keepStations() {
+	for _, n := range ns.GetFleet() {
+		if n.agenda.goal == GoalTypeFollow {
+			n.agenda.formation = ns.FleetStation(n)
+		}
+	}
+}
+
+// FleetFire ships of the player's fleet fire upon provoked ships within range
+func (ns *Npcs) FleetFire() []combat.Shot {
+	shots := []combat.Shot{}
+	for _, n := range ns.GetFleet() {
+		if n.IsSinking() || !n.ship.IsLoaded() {
+			continue
+		}
+		for _, item := range spatial.ByDistance(n.GetPos(), ns.index.QueryRadius(n.GetPos(), n.ship.GetClass().Range, spatial.KindNpc)) {
+			t := item.(*Npc)
+			d := common.Distance(n.GetPos(), t.GetPos())
+			if t == n || !t.hostile || t.IsSinking() || t.IsOwnedByPlayer() || !combat.InRange(n.ship, d) {
+				continue
+			}
+			shot := combat.Fire(n.ship, t.ship, t.GetPos(), d)
+			t.TakePlayerFire()
+			ns.logger.Infof("[%v] Fleet ship fires at [%v]: %+v", n.GetID(), t.GetID(), shot)
+			shots = append(shots, shot)
+			break
+		}
+	}
+	return shots
+}
+
+// GetVisible NPCs within the viewport centered on c, ordered by position
+func (ns *Npcs) GetVisible(c common.Coordinates, vr window.Dimensions) Npcs {
+	visible := Npcs{logger: ns.logger, index: ns.index}
+	for _, item := range ns.index.QueryRect(window.GetViewportRegion(c), spatial.KindNpc) {
+		visible.list = append(visible.list, item.(*Npc))
 	}
-	return sorted
+	return visible
 }
@@ -0,0 +1,275 @@
+package npc
+
+import (
+	"fmt"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/economy"
+	"pirate-wars/cmd/spatial"
+	"pirate-wars/cmd/town"
+	"pirate-wars/cmd/window"
+	"pirate-wars/cmd/world"
+	"pirate-wars/cmd/wreck"
+	"sync"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"go.uber.org/zap"
+)
+
+var (
+	fixtureOnce  sync.Once
+	fixtureWorld *world.MapView
+	fixtureTowns *town.Towns
+)
+
+func setup(tb testing.TB) (*world.MapView, *town.Towns) {
+	fixtureOnce.Do(func() {
+		test.NewApp()
+		test.NewWindow(nil)
+		logger := zap.NewNop().Sugar()
+		fixtureWorld = world.Init(logger)
+		fixtureTowns = town.Init(fixtureWorld, spatial.New(), logger)
+	})
+	return fixtureWorld, fixtureTowns
+}
+
+func createNpcs(tb testing.TB, count int) *Npcs {
+	w, ts := setup(tb)
+	index := spatial.New()
+	ns := Npcs{logger: zap.NewNop().Sugar(), index: index, world: w, wrecks: wreck.Init(index, zap.NewNop().Sugar())}
+	for len(ns.list) < count {
+		ns.Create(ts, w)
+	}
+	return &ns
+}
+
+func TestCalcMovementsLevelOfDetail(t *testing.T) {
+	ns := createNpcs(t, 1)
+	n := ns.list[0]
+
+	// focus far away from the NPC, it should only be simulated coarsely
+	far := common.Coordinates{X: (n.GetPos().X + common.WorldCols/2) % common.WorldCols, Y: (n.GetPos().Y + common.WorldRows/2) % common.WorldRows}
+	for i := 0; i < 10; i++ {
+		ns.CalcMovements(far)
+	}
+	if !n.coarse {
+		t.Fatalf("NPC at %v should be simulated coarsely when focus is at %v", n.GetPos(), far)
+	}
+	for _, p := range n.route {
+		if !fixtureWorld.IsPassableByBoat(p) && fixtureWorld.GetPositionType(p) != common.TerrainTypeTown {
+			t.Fatalf("coarse route passes over land at %v", p)
+		}
+	}
+
+	// bring the focus to the NPC, it should switch back to per-tile simulation
+	ns.CalcMovements(n.GetPos())
+	if n.coarse || n.route != nil {
+		t.Fatalf("NPC at %v should be simulated in detail when focused", n.GetPos())
+	}
+}
+
+// landlocked a tile with land all around it, where no route can lead anywhere
+func landlocked(tb testing.TB) common.Coordinates {
+	w, _ := setup(tb)
+	for x := 1; x < common.WorldCols-1; x++ {
+		for y := 1; y < common.WorldRows-1; y++ {
+			c := common.Coordinates{X: x, Y: y}
+			inland := w.IsLand(c)
+			for _, dir := range common.Directions {
+				inland = inland && w.IsLand(common.AddDirection(c, dir))
+			}
+			if inland {
+				return c
+			}
+		}
+	}
+	tb.Skip("no landlocked tile in this world")
+	return common.Coordinates{}
+}
+
+func TestStuckReplan(t *testing.T) {
+	ns := createNpcs(t, 1)
+	n := ns.list[0]
+	n.SetPos(landlocked(t))
+	far := common.Coordinates{X: (n.GetPos().X + common.WorldCols/2) % common.WorldCols, Y: (n.GetPos().Y + common.WorldRows/2) % common.WorldRows}
+	ns.CalcMovements(far)
+	if !n.coarse || len(n.route) != 1 || n.replanIn != ReplanTicks {
+		t.Fatalf("NPC stuck at %v should wait %d ticks before planning again, waits %d", n.GetPos(), ReplanTicks, n.replanIn)
+	}
+	for i := 0; i < ReplanTicks; i++ {
+		ns.CalcMovements(far)
+	}
+	if n.replanIn != 0 {
+		t.Fatalf("NPC should have waited out its %d ticks, %d left", ReplanTicks, n.replanIn)
+	}
+	ns.CalcMovements(far)
+	if n.replanIn != ReplanTicks {
+		t.Fatalf("NPC still stuck should plan again and wait another %d ticks, waits %d", ReplanTicks, n.replanIn)
+	}
+}
+
+func BenchmarkCalcMovements(b *testing.B) {
+	everywhere := window.Region{X: 0, Y: 0, Cols: common.WorldCols, Rows: common.WorldRows}
+	for _, count := range []int{150, 1000, 5000} {
+		ns := createNpcs(b, count)
+		focus := ns.list[0].GetPos()
+		b.Run(fmt.Sprintf("detailed-%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ns.calcMovements(everywhere)
+			}
+		})
+		b.Run(fmt.Sprintf("lod-%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ns.CalcMovements(focus)
+			}
+		})
+		// every NPC stuck where no route leads anywhere, e.g. its channel filled in
+		stuck := createNpcs(b, count)
+		inland := landlocked(b)
+		for _, n := range stuck.list {
+			n.SetPos(inland)
+		}
+		far := common.Coordinates{X: (inland.X + common.WorldCols/2) % common.WorldCols, Y: (inland.Y + common.WorldRows/2) % common.WorldRows}
+		b.Run(fmt.Sprintf("lod-stuck-%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				stuck.CalcMovements(far)
+			}
+		})
+	}
+}
+
+type LeaderMock struct {
+	pos common.Coordinates
+}
+
+func (l *LeaderMock) GetPos() common.Coordinates { return l.pos }
+
+func TestFleetFollow(t *testing.T) {
+	ns := createNpcs(t, 1)
+	n := ns.list[0]
+	leader := &LeaderMock{pos: n.GetPos()}
+	n.Capture(leader, common.PlayerFlag, FormationOffset(0))
+	if !n.IsOwnedByPlayer() || len(ns.GetFleet()) != 1 || n.GetOrders() != "Follow" {
+		t.Fatalf("captured ship should join the fleet following its leader, orders: %v", n.GetOrders())
+	}
+	for i := 0; i < 10; i++ {
+		ns.CalcMovements(leader.pos)
+	}
+	station := common.AddDirection(leader.pos, FormationOffset(0))
+	if fixtureWorld.IsPassableByBoat(station) && common.Distance(n.GetPos(), station) >= FollowDistance {
+		t.Fatalf("ship at %v should have taken its station at %v", n.GetPos(), station)
+	}
+	n.Guard()
+	pos := n.GetPos()
+	leader.pos = common.Coordinates{X: pos.X + 5, Y: pos.Y}
+	ns.CalcMovements(leader.pos)
+	if !common.CoordsMatch(pos, n.GetPos()) {
+		t.Fatalf("ship on guard should hold its position")
+	}
+}
+
+func TestFleetStation(t *testing.T) {
+	ns := createNpcs(t, 3)
+	leader := &LeaderMock{pos: ns.list[0].GetPos()}
+	for _, n := range []*Npc{ns.list[2], ns.list[0], ns.list[1]} {
+		n.Capture(leader, common.PlayerFlag, ns.FleetStation(n))
+	}
+	ns.CalcMovements(leader.pos)
+	for i, n := range ns.GetFleet() {
+		if !common.CoordsMatch(n.GetFormation(), FormationOffset(i)) {
+			t.Fatalf("ship %d of the fleet should keep station at %v, not %v", i, FormationOffset(i), n.GetFormation())
+		}
+	}
+}
+
+func TestRetargetHunters(t *testing.T) {
+	ns := createNpcs(t, 0)
+	sacked, next := &LeaderMock{}, &LeaderMock{}
+	for i := 0; i < 2; i++ {
+		ns.CreateHunter(fixtureTowns.GetTowns()[0].GetPos(), common.Flags[0], sacked)
+	}
+	if len(ns.GetHunters(sacked)) != 2 {
+		t.Fatalf("expected 2 hunters after the hideout, got %d", len(ns.GetHunters(sacked)))
+	}
+	ns.Retarget(sacked, next)
+	if len(ns.GetHunters(sacked)) != 0 || len(ns.GetHunters(next)) != 2 {
+		t.Fatalf("hunters should turn on the next target once theirs is gone")
+	}
+}
+
+func TestTrade(t *testing.T) {
+	ns := createNpcs(t, 1)
+	n := ns.list[0]
+	tt := n.targetTown()
+	m := tt.GetMarket()
+	stock := economy.Goods{}
+	for _, c := range economy.Commodities() {
+		stock[c] = m.GetStock(c)
+	}
+	t.Cleanup(func() {
+		// the towns are shared by every test, leave the market stocked as it was found
+		for c, n := range stock {
+			m.Buy(c, m.GetStock(c))
+			m.Sell(c, n)
+		}
+	})
+	c := economy.CommodityType(economy.CommoditySpices)
+	m.Buy(c, m.GetStock(c))
+	s := n.GetShip()
+	for h, units := range s.GetHold() {
+		s.UnloadCargo(h, units)
+	}
+	s.LoadCargo(c, 10)
+	n.trade(tt)
+	if m.GetStock(c) != 10 || s.GetHold()[c] != 0 {
+		t.Fatalf("expected 10 spices delivered to the market, stock %d", m.GetStock(c))
+	}
+}
+
+func TestRumour(t *testing.T) {
+	ns := createNpcs(t, 1)
+	n := ns.list[0]
+	s := n.GetShip()
+	s.LoadCargo(economy.CommoditySugar, s.GetClass().Cargo-s.GetCargo())
+	r, ok := ns.Rumour(n.GetPos(), nil)
+	if !ok || r.npc != n || r.IsStale() {
+		t.Fatalf("the tavern should hear of the laden trader nearby")
+	}
+	if _, ok := ns.Rumour(n.GetPos(), []*Rumour{r}); ok {
+		t.Fatalf("a ship already talked about shouldn't be heard of twice")
+	}
+	for c, units := range s.GetHold() {
+		s.UnloadCargo(c, units)
+	}
+	if !r.IsStale() {
+		t.Fatalf("a rumour of cargo that's been unloaded should be stale")
+	}
+	s.LoadCargo(economy.CommoditySugar, r.hold.Total())
+	for i := 0; i < RumourDays; i++ {
+		r.Age()
+	}
+	if !r.IsStale() {
+		t.Fatalf("a rumour %d days old should be stale", r.GetAge())
+	}
+}
+
+func TestFleetFireThroughFleet(t *testing.T) {
+	ns := createNpcs(t, 7)
+	base := ns.list[0].GetPos()
+	leader := &LeaderMock{pos: base}
+	for i, n := range ns.list[:6] {
+		n.Capture(leader, common.PlayerFlag, FormationOffset(i))
+		if i > 0 {
+			// crowd the first ship with the rest of the fleet, their guns still reloading
+			n.SetPos(common.AddDirection(base, common.Directions[i-1]))
+			n.GetShip().Reload()
+		}
+	}
+	target := ns.list[6]
+	target.SetPos(common.Coordinates{X: base.X + 2, Y: base.Y + 2})
+	target.Provoke()
+	shots := ns.FleetFire()
+	if len(shots) != 1 || !common.CoordsMatch(shots[0].Target, target.GetPos()) {
+		t.Fatalf("the ship crowded by its fleet should still fire on the provoked ship at %v, shots: %+v", target.GetPos(), shots)
+	}
+}
@@ -0,0 +1,113 @@
+package npc
+
+import (
+	"fmt"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/economy"
+	"strings"
+)
+
+// RumourRange tiles from a town within which its tavern hears of passing ships
+const RumourRange = 150
+
+// RumourDays days before a rumour is too old to be worth anything
+const RumourDays = 10
+
+// Rumour tavern talk of a trader under way, true for as long as the ship is still on that voyage
+// with most of its cargo aboard
+type Rumour struct {
+	npc  *Npc
+	dest common.Coordinates
+	hold economy.Goods
+	text string
+	age  int
+}
+
+func (r *Rumour) GetText() string {
+	return r.text
+}
+
+func (r *Rumour) GetAge() int {
+	return r.age
+}
+
+// Age a day passes
+func (r *Rumour) Age() {
+	r.age++
+}
+
+// IsTrue whether the ship is still afloat, in the same hands, bound for the same town and carrying
+// at least half of what it was said to be
+func (r *Rumour) IsTrue() bool {
+	n := r.npc
+	if n.IsSinking() || n.IsOwnedByPlayer() || n.agenda.goal != GoalTypeTrade {
+		return false
+	}
+	if !common.CoordsMatch(n.targetTown().GetPos(), r.dest) {
+		return false
+	}
+	return n.ship.GetCargo()*2 >= r.hold.Total()
+}
+
+// IsStale whether the rumour is too old, or the world has moved on since it was heard
+func (r *Rumour) IsStale() bool {
+	return r.age >= RumourDays || !r.IsTrue()
+}
+
+// Rumour what the tavern has heard of the richest trader under way near the town, leaving out
+// the ships already talked about
+func (ns *Npcs) Rumour(near common.Coordinates, heard []*Rumour) (*Rumour, bool) {
+	var richest *Npc
+	for _, n := range ns.list {
+		if n.IsSinking() || n.IsOwnedByPlayer() || n.agenda.goal != GoalTypeTrade || n.ship.GetCargo() == 0 {
+			continue
+		}
+		if common.Distance(near, n.GetPos()) > RumourRange || isHeard(n, heard) {
+			continue
+		}
+		if richest == nil || n.ship.GetCargo() > richest.ship.GetCargo() {
+			richest = n
+		}
+	}
+	if richest == nil {
+		return nil, false
+	}
+	hold := economy.Goods{}
+	hold.Add(richest.ship.GetHold())
+	from := richest.agenda.tadeRoute[richest.agenda.tradeTarget^1]
+	to := richest.targetTown()
+	return &Rumour{
+		npc:  richest,
+		dest: to.GetPos(),
+		hold: hold,
+		text: fmt.Sprintf("%s %s %s loaded with %s is sailing from %s to %s", article(richest.GetFlag()), richest.GetFlag(),
+			strings.ToLower(richest.ship.GetClass().Name), describeHold(hold), from.GetName(), to.GetName()),
+	}, true
+}
+
+func isHeard(n *Npc, heard []*Rumour) bool {
+	for _, r := range heard {
+		if r.npc == n {
+			return true
+		}
+	}
+	return false
+}
+
+// describeHold the main part of the cargo, as talked about in taverns
+func describeHold(hold economy.Goods) string {
+	most := economy.Commodities()[0]
+	for _, c := range economy.Commodities() {
+		if hold[c] > hold[most] {
+			most = c
+		}
+	}
+	return fmt.Sprintf("%d %s", hold[most], strings.ToLower(economy.CommodityLookup[most].Name))
+}
+
+func article(word string) string {
+	if strings.ContainsRune("AEIOU", rune(word[0])) {
+		return "An"
+	}
+	return "A"
+}
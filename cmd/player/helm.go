@@ -0,0 +1,129 @@
+package player
+
+import (
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/ship"
+)
+
+// MaxCanvas sail settings, from bare poles up to all plain sail
+const MaxCanvas = 4
+
+// Acceleration fraction of the difference to the speed her sails can give her the ship gains or
+// loses each tick
+const Acceleration = 0.2
+
+// MinTurn points per tick the bow falls off even with no way on, so a ship in irons can pay off
+const MinTurn = 0.1
+
+// pointsOfSail fraction of her best speed a ship makes by how many points her heading is off the
+// wind's path, from running before it to lying head to wind in irons
+var pointsOfSail = []float64{0.7, 1, 0.85, 0.5, 0}
+
+// Helm the heading the player's ship steers, the canvas she carries and the way she has on
+type Helm struct {
+	heading  int     // compass point the bow faces
+	canvas   int     // sail set, up to MaxCanvas
+	speed    float64 // tiles per tick
+	progress float64 // way made towards the next tile
+	turn     int     // points the helm is still over, positive to starboard
+	swing    float64 // how far the bow has come round towards the next point
+}
+
+func (h *Helm) GetHeading() int {
+	return h.heading
+}
+
+func (h *Helm) GetCanvas() int {
+	return h.canvas
+}
+
+// GetSpeed tiles per tick the ship is making
+func (h *Helm) GetSpeed() float64 {
+	return h.speed
+}
+
+// Turn puts the helm over to come round a point to starboard (1) or to port (-1)
+func (h *Helm) Turn(dir int) {
+	if abs(h.turn+dir) <= len(common.Compass)/2 {
+		h.turn += dir
+	}
+}
+
+// Steer puts the helm over to come round the shorter way onto the compass point
+func (h *Helm) Steer(point int) {
+	n := len(common.Compass)
+	h.turn = (point-h.heading+n+n/2)%n - n/2
+	if h.turn == 0 {
+		h.swing = 0
+	}
+}
+
+// Trim sets more (positive) or less (negative) sail
+func (h *Helm) Trim(n int) {
+	h.canvas = max(0, min(MaxCanvas, h.canvas+n))
+}
+
+// Stop the ship loses all way, brought up short by the coast
+func (h *Helm) Stop() {
+	h.speed, h.progress = 0, 0
+}
+
+// Moor the sails are furled and the ship lies alongside or at anchor
+func (h *Helm) Moor() {
+	h.canvas = 0
+	h.Stop()
+}
+
+// offWind points the heading is off the path the wind blows towards, 0 running before it up to 4
+// head to wind
+func (h *Helm) offWind(wind common.Coordinates) int {
+	w := common.CompassPoint(wind)
+	if w < 0 {
+		return 0
+	}
+	off := abs(h.heading - w)
+	return min(off, len(common.Compass)-off)
+}
+
+// turningRate points per tick the bow comes round, a ship needs way on to answer the helm and the
+// deeper her draft the wider she turns
+func (h *Helm) turningRate(s *ship.Ship) float64 {
+	radius := float64(s.GetClass().Draft+1) / 2
+	return max(MinTurn, h.speed/radius)
+}
+
+// Tick the ship gathers or loses way towards the speed her sails give her on this point of sail,
+// swings round if the helm is over, and returns the direction of the next tile once she has
+// covered it
+func (h *Helm) Tick(s *ship.Ship, wind common.Coordinates) (common.Coordinates, bool) {
+	target := s.GetSpeed() * pointsOfSail[h.offWind(wind)] * float64(h.canvas) / MaxCanvas
+	h.speed += (target - h.speed) * Acceleration
+	if h.speed < 0.01 {
+		h.speed = 0
+	}
+	if h.turn != 0 {
+		h.swing += h.turningRate(s)
+		for h.swing >= 1 && h.turn != 0 {
+			dir := h.turn / abs(h.turn)
+			h.heading = (h.heading + len(common.Compass) + dir) % len(common.Compass)
+			h.turn -= dir
+			h.swing -= 1
+		}
+		if h.turn == 0 {
+			h.swing = 0
+		}
+	}
+	h.progress += h.speed
+	if h.progress < 1 {
+		return common.Coordinates{}, false
+	}
+	h.progress -= 1
+	return common.Compass[h.heading], true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,69 @@
+package player
+
+import (
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/ship"
+	"testing"
+)
+
+func TestHelm(t *testing.T) {
+	s := ship.Create(ship.ClassSloop)
+	south := common.Compass[4]
+	h := &Helm{}
+	h.Trim(MaxCanvas + 1)
+	if h.GetCanvas() != MaxCanvas {
+		t.Fatalf("expected no more than %d sail set, got %d", MaxCanvas, h.GetCanvas())
+	}
+	for i := 0; i < 20; i++ {
+		h.Tick(s, south)
+	}
+	if h.GetSpeed() > 0.05 {
+		t.Fatalf("a ship head to wind should lie in irons, making %.2f", h.GetSpeed())
+	}
+	h.Turn(1)
+	h.Turn(1)
+	for i := 0; i < 40 && h.GetHeading() != 2; i++ {
+		h.Tick(s, south)
+	}
+	if h.GetHeading() != 2 {
+		t.Fatalf("expected the ship to pay off onto a beam reach heading east, heading %d", h.GetHeading())
+	}
+	moved := false
+	for i := 0; i < 40; i++ {
+		if d, ok := h.Tick(s, south); ok {
+			moved = common.CoordsMatch(d, common.Compass[2])
+		}
+	}
+	reach := h.GetSpeed()
+	if !moved || reach < s.GetSpeed()*pointsOfSail[2]*0.9 {
+		t.Fatalf("expected the ship to gather way on a reach, making %.2f", reach)
+	}
+	h.Trim(-MaxCanvas / 2)
+	h.Tick(s, south)
+	if h.GetSpeed() >= reach {
+		t.Fatalf("expected the ship to slow with less sail set")
+	}
+	h.Moor()
+	if h.GetSpeed() != 0 || h.GetCanvas() != 0 {
+		t.Fatalf("a moored ship should have no sail set nor way on")
+	}
+}
+
+func TestSteer(t *testing.T) {
+	s := ship.Create(ship.ClassSloop)
+	h := &Helm{}
+	h.Steer(7)
+	for i := 0; i < 20 && h.GetHeading() == 0; i++ {
+		h.Tick(s, common.Compass[2])
+	}
+	if h.GetHeading() != 7 {
+		t.Fatalf("expected the ship to come round to port onto NW, heading %d", h.GetHeading())
+	}
+	h.Steer(3)
+	for i := 0; i < 200 && h.GetHeading() != 3; i++ {
+		h.Tick(s, common.Compass[2])
+	}
+	if h.GetHeading() != 3 || h.turn != 0 {
+		t.Fatalf("expected the ship to settle on SE, heading %d with %d points of helm on", h.GetHeading(), h.turn)
+	}
+}
@@ -0,0 +1,74 @@
+package player
+
+import (
+	"image"
+	"image/color"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/entities"
+	"pirate-wars/cmd/resources"
+	"pirate-wars/cmd/spatial"
+	"pirate-wars/cmd/window"
+)
+
+// Party the crew the player has taken ashore, while the ship lies at anchor
+type Party struct {
+	avatar entities.Avatar
+}
+
+func (p *Party) GetID() string {
+	return p.avatar.GetID()
+}
+
+func (p *Party) GetPos() common.Coordinates {
+	return p.avatar.GetPos()
+}
+
+func (p *Party) GetPreviousPos() common.Coordinates {
+	return p.avatar.GetPreviousPos()
+}
+
+func (p *Party) SetPos(c common.Coordinates) {
+	p.avatar.SetPos(c)
+}
+
+func (p *Party) GetTileImage() image.Image {
+	return p.avatar.GetTileImage()
+}
+
+func (p *Party) GetViewableRange() window.Dimensions {
+	return p.avatar.GetViewableRange()
+}
+
+func (p *Party) IsHighlighted() bool {
+	return p.avatar.IsHighlighted()
+}
+
+func (p *Party) GetColor() color.Color {
+	return p.avatar.GetColor()
+}
+
+// Land puts a party ashore at c, leaving the ship at anchor
+func (p *Player) Land(c common.Coordinates, index *spatial.Index) *Party {
+	p.party = &Party{avatar: entities.CreateAvatar(c, resources.GetPartyTile(), color.White)}
+	index.Insert(p.party, spatial.KindParty)
+	p.party.avatar.Track(index)
+	return p.party
+}
+
+// Embark the landing party returns aboard
+func (p *Player) Embark(index *spatial.Index) {
+	if p.party == nil {
+		return
+	}
+	index.Remove(p.party.GetID())
+	p.party = nil
+}
+
+// GetParty the landing party, if the player has gone ashore
+func (p *Player) GetParty() *Party {
+	return p.party
+}
+
+func (p *Player) IsAshore() bool {
+	return p.party != nil
+}
@@ -0,0 +1,26 @@
+package player
+
+import (
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/spatial"
+	"testing"
+)
+
+func TestLandingParty(t *testing.T) {
+	index := spatial.New()
+	p := &Player{}
+	landing := common.Coordinates{X: 10, Y: 10}
+	party := p.Land(landing, index)
+	if !p.IsAshore() || len(index.QueryRadius(landing, 0, spatial.KindParty)) != 1 {
+		t.Fatalf("the party should be ashore at %v", landing)
+	}
+	inland := common.Coordinates{X: 11, Y: 10}
+	party.SetPos(inland)
+	if len(index.QueryRadius(inland, 0, spatial.KindParty)) != 1 {
+		t.Fatalf("the index should follow the party inland")
+	}
+	p.Embark(index)
+	if p.IsAshore() || len(index.QueryRadius(inland, 1, spatial.KindParty)) != 0 {
+		t.Fatalf("the party should be back aboard")
+	}
+}
@@ -1,14 +1,99 @@
 package player
 
 import (
+	"image"
 	"image/color"
 	"pirate-wars/cmd/common"
 	"pirate-wars/cmd/entities"
 	"pirate-wars/cmd/resources"
+	"pirate-wars/cmd/ship"
+	"pirate-wars/cmd/spatial"
+	"pirate-wars/cmd/window"
 	"pirate-wars/cmd/world"
 )
 
-func Create(world *world.MapView) *entities.Avatar {
-	p := entities.CreateAvatar(world.RandomPositionDeepWater(), resources.GetShipTile(common.ShipWhite), color.White)
+// StartingGold coin the player sets out with
+const StartingGold = 100
+
+type Player struct {
+	avatar entities.Avatar
+	ship   *ship.Ship
+	gold   int
+	party  *Party
+	helm   Helm
+}
+
+func (p *Player) GetID() string {
+	return p.avatar.GetID()
+}
+
+func (p *Player) GetPos() common.Coordinates {
+	return p.avatar.GetPos()
+}
+
+func (p *Player) GetPreviousPos() common.Coordinates {
+	return p.avatar.GetPreviousPos()
+}
+
+func (p *Player) SetPos(c common.Coordinates) {
+	p.avatar.SetPos(c)
+}
+
+func (p *Player) GetTileImage() image.Image {
+	if p.ship.IsSunk() {
+		return resources.GetWreckTile(common.ShipWhite)
+	}
+	return resources.GetRotatedShipTile(common.ShipWhite, p.ship.GetRefitMarks(), p.helm.GetHeading())
+}
+
+func (p *Player) GetViewableRange() window.Dimensions {
+	return p.avatar.GetViewableRange()
+}
+
+func (p *Player) IsHighlighted() bool {
+	return p.avatar.IsHighlighted()
+}
+
+func (p *Player) GetColor() color.Color {
+	return p.avatar.GetColor()
+}
+
+func (p *Player) GetFlag() string {
+	return common.PlayerFlag.Name
+}
+
+func (p *Player) GetShip() *ship.Ship {
+	return p.ship
+}
+
+// SetShip the player takes command of another ship
+func (p *Player) SetShip(s *ship.Ship) {
+	p.ship = s
+}
+
+func (p *Player) GetHelm() *Helm {
+	return &p.helm
+}
+
+func (p *Player) GetGold() int {
+	return p.gold
+}
+
+func (p *Player) AddGold(n int) {
+	p.gold += n
+}
+
+func (p *Player) SpendGold(n int) {
+	p.gold -= n
+}
+
+func Create(world *world.MapView, index *spatial.Index) *Player {
+	p := Player{
+		avatar: entities.CreateAvatar(world.RandomPositionDeepWater(), resources.GetShipTile(common.ShipWhite), color.White),
+		ship:   ship.Create(ship.ClassBrigantine),
+		gold:   StartingGold,
+	}
+	index.Insert(&p, spatial.KindPlayer)
+	p.avatar.Track(index)
 	return &p
 }
@@ -0,0 +1,224 @@
+package resources
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"pirate-wars/cmd/common"
+)
+
+const (
+	EffectHit  = 1
+	EffectMiss = 2
+)
+
+type EffectType int
+
+var (
+	effectCache = make(map[EffectType]image.Image)
+	wreckCache  = make(map[common.ShipType]image.Image)
+)
+
+// effectColors inner and outer colour of each effect
+var effectColors = map[EffectType][2]color.RGBA{
+	EffectHit:  {{255, 230, 120, 255}, {220, 70, 20, 230}},
+	EffectMiss: {{255, 255, 255, 255}, {170, 210, 255, 200}},
+}
+
+// GetEffectTile returns a tile of a cannon ball hitting (explosion) or missing (splash)
+func GetEffectTile(e EffectType) image.Image {
+	if cached, ok := effectCache[e]; ok {
+		return cached
+	}
+	colors := effectColors[e]
+	img := image.NewRGBA(image.Rect(0, 0, TileSize, TileSize))
+	center := TileSize / 2
+	for y := 0; y < TileSize; y++ {
+		for x := 0; x < TileSize; x++ {
+			d := (x-center)*(x-center) + (y-center)*(y-center)
+			if d < (TileSize/6)*(TileSize/6) {
+				img.Set(x, y, colors[0])
+			} else if d < (TileSize/3)*(TileSize/3) {
+				img.Set(x, y, colors[1])
+			}
+		}
+	}
+	effectCache[e] = img
+	return img
+}
+
+// GetWreckTile returns a faded, greyed out version of a ship tile
+func GetWreckTile(s common.ShipType) image.Image {
+	if cached, ok := wreckCache[s]; ok {
+		return cached
+	}
+	ship := GetShipTile(s)
+	img := image.NewRGBA(image.Rect(0, 0, TileSize, TileSize))
+	for y := 0; y < TileSize; y++ {
+		for x := 0; x < TileSize; x++ {
+			r, g, b, a := ship.At(x, y).RGBA()
+			grey := uint8((r + g + b) / 3 >> 9)
+			img.Set(x, y, color.RGBA{grey, grey, grey, uint8(a>>8) / 2})
+		}
+	}
+	wreckCache[s] = img
+	return img
+}
+
+var markedCache = make(map[string]image.Image)
+
+// GetMarkedShipTile returns a ship tile with a pennant for each mark flying along its foot, e.g.
+// to show how the ship has been refitted
+func GetMarkedShipTile(s common.ShipType, marks []color.RGBA) image.Image {
+	if len(marks) == 0 {
+		return GetShipTile(s)
+	}
+	key := fmt.Sprint(s, marks)
+	if cached, ok := markedCache[key]; ok {
+		return cached
+	}
+	ship := GetShipTile(s)
+	img := image.NewRGBA(image.Rect(0, 0, TileSize, TileSize))
+	draw.Draw(img, img.Bounds(), ship, image.Point{}, draw.Src)
+	size := max(2, TileSize/8)
+	for i, m := range marks {
+		left := TileSize/8 + i*(size+1)
+		for y := TileSize - size - 1; y < TileSize-1; y++ {
+			for x := left; x < left+size; x++ {
+				img.Set(x, y, m)
+			}
+		}
+	}
+	markedCache[key] = img
+	return img
+}
+
+var rotatedCache = make(map[string]image.Image)
+
+// GetRotatedShipTile returns a marked ship tile turned to head towards one of the compass points,
+// clockwise from north where the tileset has the bow
+func GetRotatedShipTile(s common.ShipType, marks []color.RGBA, point int) image.Image {
+	ship := GetMarkedShipTile(s, marks)
+	if point == 0 {
+		return ship
+	}
+	key := fmt.Sprint(s, marks, point)
+	if cached, ok := rotatedCache[key]; ok {
+		return cached
+	}
+	img := image.NewRGBA(image.Rect(0, 0, TileSize, TileSize))
+	sin, cos := math.Sincos(float64(point) * 2 * math.Pi / float64(len(common.Compass)))
+	center := float64(TileSize-1) / 2
+	for y := 0; y < TileSize; y++ {
+		for x := 0; x < TileSize; x++ {
+			// turn each pixel back onto the upright tile to find what belongs there
+			dx, dy := float64(x)-center, float64(y)-center
+			sx := int(math.Round(cos*dx + sin*dy + center))
+			sy := int(math.Round(-sin*dx + cos*dy + center))
+			if sx >= 0 && sx < TileSize && sy >= 0 && sy < TileSize {
+				img.Set(x, y, ship.At(sx, sy))
+			}
+		}
+	}
+	rotatedCache[key] = img
+	return img
+}
+
+var hideoutCache image.Image
+
+// GetHideoutTile returns a town tile, darkened, with a black flag flying over it
+func GetHideoutTile() image.Image {
+	if hideoutCache != nil {
+		return hideoutCache
+	}
+	town := GetTerrainTile(common.TerrainTypeTown)
+	img := image.NewRGBA(image.Rect(0, 0, TileSize, TileSize))
+	for y := 0; y < TileSize; y++ {
+		for x := 0; x < TileSize; x++ {
+			r, g, b, a := town.At(x, y).RGBA()
+			img.Set(x, y, color.RGBA{uint8(r >> 9), uint8(g >> 9), uint8(b >> 9), uint8(a >> 8)})
+		}
+	}
+	pole := color.RGBA{110, 80, 40, 255}
+	flag := color.RGBA{10, 10, 10, 255}
+	skull := color.RGBA{240, 240, 240, 255}
+	left, top := TileSize/3, TileSize/8
+	for y := top; y < TileSize-TileSize/8; y++ {
+		img.Set(left, y, pole)
+	}
+	for y := top; y < top+TileSize/3; y++ {
+		for x := left + 1; x < left+TileSize/2; x++ {
+			img.Set(x, y, flag)
+		}
+	}
+	img.Set(left+TileSize/4, top+TileSize/6, skull)
+	img.Set(left+TileSize/4+1, top+TileSize/6, skull)
+	hideoutCache = img
+	return img
+}
+
+var fortCache image.Image
+
+// GetFortTile returns a stone tower with battlements, drawn over the town it guards
+func GetFortTile() image.Image {
+	if fortCache != nil {
+		return fortCache
+	}
+	stone := color.RGBA{150, 150, 150, 255}
+	mortar := color.RGBA{90, 90, 90, 255}
+	img := image.NewRGBA(image.Rect(0, 0, TileSize, TileSize))
+	left, right := TileSize/4, TileSize*3/4
+	top, bottom := TileSize/4, TileSize-TileSize/8
+	merlon := max(1, TileSize/8)
+	for y := top - merlon; y < bottom; y++ {
+		for x := left; x < right; x++ {
+			if y < top && ((x-left)/merlon)%2 == 1 {
+				// gaps between the battlements
+				continue
+			}
+			c := stone
+			if x == left || x == right-1 || y == bottom-1 || (y-top)%(merlon*2) == 0 {
+				c = mortar
+			}
+			img.Set(x, y, c)
+		}
+	}
+	fortCache = img
+	return img
+}
+
+var partyCache image.Image
+
+// GetPartyTile returns a few sailors ashore, a landing party
+func GetPartyTile() image.Image {
+	if partyCache != nil {
+		return partyCache
+	}
+	skin := color.RGBA{225, 180, 140, 255}
+	shirt := color.RGBA{240, 240, 240, 255}
+	breeches := color.RGBA{60, 60, 110, 255}
+	img := image.NewRGBA(image.Rect(0, 0, TileSize, TileSize))
+	unit := max(1, TileSize/16)
+	for i, left := range []int{TileSize / 5, TileSize * 2 / 5, TileSize * 3 / 5} {
+		top := TileSize/4 + (i%2)*unit*2
+		for y := 0; y < unit*9; y++ {
+			c := breeches
+			if y < unit*2 {
+				c = skin
+			} else if y < unit*5 {
+				c = shirt
+			}
+			for x := 0; x < unit*2; x++ {
+				if y >= unit*5 && x%(unit*2) >= unit && y%2 == 0 {
+					// a gap between the legs
+					continue
+				}
+				img.Set(left+x, top+y, c)
+			}
+		}
+	}
+	partyCache = img
+	return img
+}
@@ -80,6 +80,10 @@ func GetShipTile(s common.ShipType) image.Image {
 
 // GetTerrainTile returns the image for a specific terrain type
 func GetTerrainTile(tt common.TerrainType) image.Image {
+	if tt == common.TerrainTypeHideout {
+		// not on the tileset, drawn over a town tile
+		return GetHideoutTile()
+	}
 	idx := int(tt)
 	return getTileByRegion(idx)
 }
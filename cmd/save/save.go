@@ -0,0 +1,61 @@
+package save
+
+import (
+	"encoding/json"
+	"os"
+	"pirate-wars/cmd/common"
+)
+
+// Town a town as it stands on the day of the save
+type Town struct {
+	Name       string               `json:"name"`
+	Nation     string               `json:"nation"`
+	Size       string               `json:"size"`
+	Population int                  `json:"population"`
+	Tiles      []common.Coordinates `json:"tiles"`
+}
+
+// Hideout one of the player's hideouts, with what's in its stash by commodity name
+type Hideout struct {
+	Pos      common.Coordinates `json:"pos"`
+	Garrison int                `json:"garrison"`
+	Defences int                `json:"defences"`
+	Tavern   bool               `json:"tavern"`
+	Stash    map[string]int     `json:"stash"`
+}
+
+// Player the player's position, ship and purse
+type Player struct {
+	Pos   common.Coordinates `json:"pos"`
+	Class string             `json:"class"`
+	Hull  int                `json:"hull"`
+	Crew  int                `json:"crew"`
+	Gold  int                `json:"gold"`
+}
+
+type Game struct {
+	Day      int       `json:"day"`
+	Player   Player    `json:"player"`
+	Towns    []Town    `json:"towns"`
+	Hideouts []Hideout `json:"hideouts"`
+}
+
+// Write saves the game as JSON to path, replacing any earlier save
+func Write(path string, g Game) error {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Read loads a game saved by Write
+func Read(path string) (Game, error) {
+	var g Game
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return g, err
+	}
+	err = json.Unmarshal(data, &g)
+	return g, err
+}
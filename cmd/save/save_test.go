@@ -0,0 +1,29 @@
+package save
+
+import (
+	"path/filepath"
+	"pirate-wars/cmd/common"
+	"testing"
+)
+
+func TestWriteRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), common.SaveFile)
+	g := Game{
+		Day:    12,
+		Player: Player{Pos: common.Coordinates{X: 3, Y: 4}, Class: "Sloop", Hull: 30, Crew: 20, Gold: 150},
+		Towns: []Town{
+			{Name: "Port Salty Bay", Nation: "Dutch", Size: "Village", Population: 620, Tiles: []common.Coordinates{{X: 10, Y: 11}, {X: 10, Y: 12}}},
+		},
+	}
+	if err := Write(path, g); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	l, err := Read(path)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if l.Day != g.Day || l.Player != g.Player || len(l.Towns) != 1 || l.Towns[0].Name != "Port Salty Bay" ||
+		l.Towns[0].Nation != "Dutch" || l.Towns[0].Population != 620 || len(l.Towns[0].Tiles) != 2 {
+		t.Fatalf("loaded game %+v doesn't match saved %+v", l, g)
+	}
+}
@@ -0,0 +1,157 @@
+package ship
+
+import "math/rand"
+
+// MaxMorale a crew couldn't be any happier
+const MaxMorale = 100
+
+// StartingMorale morale of a freshly signed on crew
+const StartingMorale = 70
+
+// LowMorale below which the crew drags its feet, sailing slower and fighting less fiercely
+const LowMorale = 40
+
+// MutinyMorale below which the crew may rise up against its captain
+const MutinyMorale = 20
+
+// MutinyChance daily percentage chance of a mutiny breaking out once morale is low enough
+const MutinyChance = 25
+
+// CrewPerShare crew members sharing each gold of daily wages, and each unit of food and water
+const CrewPerShare = 10
+
+// VoyageDays days at sea the crew puts up with before longing for port
+const VoyageDays = 10
+
+// UnpaidMorale morale lost each day the wages aren't paid
+const UnpaidMorale = 10
+
+// VoyageMorale morale lost each day of a long voyage
+const VoyageMorale = 2
+
+// ShoreLeaveMorale morale regained each day spent in port
+const ShoreLeaveMorale = 5
+
+// VictoryMorale morale gained by winning a fight
+const VictoryMorale = 5
+
+// DefeatMorale morale lost by losing a fight
+const DefeatMorale = 10
+
+// PlunderMorale morale gained by taking plunder
+const PlunderMorale = 10
+
+const (
+	RationsShort = 0
+	RationsFull  = 1
+	RationsGrog  = 2
+)
+
+type RationsType int
+
+// Rations what the crew is given to eat and drink, Food is the percentage of a full ration and
+// Cost the gold spent on rum per share of the crew each day
+type Rations struct {
+	Name   string
+	Food   int
+	Cost   int
+	Morale int
+}
+
+var RationsLookup = map[RationsType]Rations{
+	RationsShort: {Name: "Short", Food: 50, Cost: 0, Morale: -3},
+	RationsFull:  {Name: "Full", Food: 100, Cost: 0, Morale: 0},
+	RationsGrog:  {Name: "Full with rum", Food: 100, Cost: 1, Morale: 2},
+}
+
+func (s *Ship) GetMorale() int {
+	return s.morale
+}
+
+func (s *Ship) ChangeMorale(n int) {
+	s.morale = min(MaxMorale, max(0, s.morale+n))
+}
+
+// GetMood a word for how the crew feels
+func (s *Ship) GetMood() string {
+	if s.morale < MutinyMorale {
+		return "Mutinous"
+	} else if s.morale < LowMorale {
+		return "Grumbling"
+	} else if s.morale < StartingMorale {
+		return "Content"
+	}
+	return "Cheerful"
+}
+
+// GetEffort percentage of its strength the crew puts into sailing and fighting
+func (s *Ship) GetEffort() int {
+	if s.morale >= LowMorale {
+		return 100
+	}
+	return 50 + 50*s.morale/LowMorale
+}
+
+func (s *Ship) GetRations() RationsType {
+	return s.rations
+}
+
+func (s *Ship) SetRations(r RationsType) {
+	s.rations = r
+}
+
+// GetUpkeep gold needed each day for the crew's wages and rum
+func (s *Ship) GetUpkeep() int {
+	return shares(s.crew) * (1 + RationsLookup[s.rations].Cost)
+}
+
+func (s *Ship) GetDaysAtSea() int {
+	return s.daysAtSea
+}
+
+// NewDay the crew eats through the stores, unless ashore, and its morale changes with their pay,
+// rations and time away from port, returns the change. Going hungry for too long the crew falls sick.
+func (s *Ship) NewDay(paid bool, inPort bool) int {
+	change := RationsLookup[s.rations].Morale
+	if !paid {
+		change -= UnpaidMorale
+	}
+	if inPort {
+		s.daysAtSea = 0
+		s.hungryDays = 0
+		change += ShoreLeaveMorale
+	} else {
+		s.daysAtSea++
+		if s.daysAtSea > VoyageDays {
+			change -= VoyageMorale
+		}
+		if s.consume() {
+			s.hungryDays = 0
+		} else {
+			s.hungryDays++
+			change -= HungerMorale
+			if s.hungryDays > StarvationDays {
+				s.LoseCrew(max(1, s.crew*SicknessLoss/100))
+			}
+		}
+	}
+	before := s.morale
+	s.ChangeMorale(change)
+	return s.morale - before
+}
+
+// IsMutinous whether a mutiny breaks out today
+func (s *Ship) IsMutinous() bool {
+	return s.crew > 0 && s.morale < MutinyMorale && rand.Intn(100) < MutinyChance
+}
+
+// Mutiny the lower the morale the likelier the mutineers take the ship, returns true if they did.
+// A mutiny that is put down costs the ringleaders, and leaves the crew subdued.
+func (s *Ship) Mutiny() bool {
+	if rand.Intn(MutinyMorale) >= s.morale {
+		return true
+	}
+	s.LoseCrew(max(1, s.crew/10))
+	s.morale = MutinyMorale
+	return false
+}
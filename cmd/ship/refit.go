@@ -0,0 +1,112 @@
+package ship
+
+import (
+	"image/color"
+	"pirate-wars/cmd/economy"
+	"sort"
+)
+
+// ValueShare percentage of a ship's worth a shipyard pays for it
+const ValueShare = 60
+
+const (
+	RefitCannons = 1
+	RefitCopper  = 2
+	RefitHold    = 3
+	RefitSails   = 4
+)
+
+type RefitType int
+
+// Refit a module a shipyard fits to a ship. Cannons, Hull and Cargo are percentages added to the
+// class's stats, Speed is added as is, and Price is a percentage of the class's price. Mark is
+// the colour the refit shows on the ship's tile.
+type Refit struct {
+	Name    string
+	Cannons int
+	Hull    int
+	Cargo   int
+	Speed   int
+	Price   int
+	Mark    color.RGBA
+}
+
+var RefitLookup = map[RefitType]Refit{
+	RefitCannons: {Name: "Extra cannons", Cannons: 50, Cargo: -20, Price: 30, Mark: color.RGBA{30, 30, 30, 255}},
+	RefitCopper:  {Name: "Copper sheathing", Hull: 25, Speed: 1, Price: 25, Mark: color.RGBA{184, 115, 51, 255}},
+	RefitHold:    {Name: "Bigger hold", Cargo: 50, Cannons: -20, Price: 20, Mark: color.RGBA{139, 90, 43, 255}},
+	RefitSails:   {Name: "Better sails", Speed: 1, Price: 15, Mark: color.RGBA{250, 250, 240, 255}},
+}
+
+// Refits every refit, in order
+func Refits() []RefitType {
+	list := []RefitType{}
+	for r := range RefitLookup {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+var refitOrder = Refits()
+
+func (r Refit) apply(c Class) Class {
+	c.Cannons += c.Cannons * r.Cannons / 100
+	c.Hull += c.Hull * r.Hull / 100
+	c.Cargo += c.Cargo * r.Cargo / 100
+	c.Speed += r.Speed
+	return c
+}
+
+func (s *Ship) HasRefit(r RefitType) bool {
+	return s.refits[r]
+}
+
+// GetRefitPrice what fitting the refit to this ship costs
+func (s *Ship) GetRefitPrice(r RefitType) int {
+	return ClassLookup[s.class].Price * RefitLookup[r].Price / 100
+}
+
+// AddRefit fits the module, the hull gains any extra planking undamaged
+func (s *Ship) AddRefit(r RefitType) {
+	hull := s.GetClass().Hull
+	s.refits[r] = true
+	s.hull += s.GetClass().Hull - hull
+}
+
+// GetRefitMarks the colours of the ship's refits, to show on its tile
+func (s *Ship) GetRefitMarks() []color.RGBA {
+	marks := []color.RGBA{}
+	for _, r := range refitOrder {
+		if s.refits[r] {
+			marks = append(marks, RefitLookup[r].Mark)
+		}
+	}
+	return marks
+}
+
+// GetValue what a shipyard pays for the ship, less the repairs it needs
+func (s *Ship) GetValue() int {
+	value := ClassLookup[s.class].Price
+	for r := range s.refits {
+		value += s.GetRefitPrice(r)
+	}
+	value = value * s.hull / s.GetClass().Hull
+	return max(0, value*ValueShare/100)
+}
+
+// Transfer moves the crew, stores, cargo and strongbox of old aboard, as much as there's room for
+func (s *Ship) Transfer(old *Ship) {
+	s.crew = min(old.crew, s.GetClass().Crew)
+	s.carpenters = min(max(1, s.GetClass().Crew/CrewPerCarpenter), s.crew)
+	s.morale = old.morale
+	s.rations = old.rations
+	s.daysAtSea = old.daysAtSea
+	s.hungryDays = old.hungryDays
+	s.hold = economy.Goods{}
+	s.LoadGoods(old.hold)
+	for t := range SupplyLookup {
+		s.supplies[t] = min(old.supplies[t], s.GetSupplyCapacity(t))
+	}
+	s.gold = old.gold
+}
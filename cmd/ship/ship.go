@@ -0,0 +1,305 @@
+package ship
+
+import (
+	"math/rand"
+	"pirate-wars/cmd/economy"
+)
+
+// ReloadTicks ticks it takes a crew to reload after firing a broadside
+const ReloadTicks = 4
+
+// CrewPerCannon crew needed to man a single cannon
+const CrewPerCannon = 2
+
+// MaxSails condition of undamaged sails, in percent
+const MaxSails = 100
+
+// CrewPerCarpenter one in so many of the starting crew is a carpenter
+const CrewPerCarpenter = 30
+
+// CarpenterRepairs hull points a carpenter patches up each day at sea
+const CarpenterRepairs = 2
+
+// AgroundChance percentage chance, per draft level beyond the shallowest, of scraping the hull on
+// each move through shallow water
+const AgroundChance = 10
+
+// SpeedScale class speed that covers one tile per tick
+const SpeedScale = 10
+
+const (
+	ClassSloop      = 1
+	ClassBrigantine = 2
+	ClassFrigate    = 3
+	ClassGalleon    = 4
+	ClassManOfWar   = 5
+)
+
+type ClassType int
+
+// Class the stats every ship of a kind shares. Speed is in tenths of a tile per tick, Draft
+// how deep the hull sits in the water (1 being the shallowest) and Price what a shipyard asks for
+// a new one.
+type Class struct {
+	Name    string
+	Speed   int
+	Hull    int
+	Cannons int
+	Range   int
+	Crew    int
+	Cargo   int
+	Draft   int
+	Price   int
+	rarity  int
+}
+
+var ClassLookup = map[ClassType]Class{
+	ClassSloop:      {Name: "Sloop", Speed: 8, Hull: 40, Cannons: 6, Range: 4, Crew: 30, Cargo: 50, Draft: 1, Price: 400, rarity: 35},
+	ClassBrigantine: {Name: "Brigantine", Speed: 7, Hull: 60, Cannons: 12, Range: 5, Crew: 60, Cargo: 120, Draft: 2, Price: 900, rarity: 30},
+	ClassFrigate:    {Name: "Frigate", Speed: 6, Hull: 90, Cannons: 24, Range: 6, Crew: 120, Cargo: 150, Draft: 3, Price: 2000, rarity: 20},
+	ClassGalleon:    {Name: "Galleon", Speed: 4, Hull: 120, Cannons: 30, Range: 5, Crew: 200, Cargo: 300, Draft: 4, Price: 3000, rarity: 10},
+	ClassManOfWar:   {Name: "Man-of-war", Speed: 3, Hull: 200, Cannons: 60, Range: 7, Crew: 400, Cargo: 200, Draft: 5, Price: 6000, rarity: 5},
+}
+
+// MaxRange the furthest any ship class can fire
+func MaxRange() int {
+	r := 0
+	for _, c := range ClassLookup {
+		r = max(r, c.Range)
+	}
+	return r
+}
+
+type Ship struct {
+	class      ClassType
+	hull       int
+	sails      int
+	crew       int
+	carpenters int
+	hold       economy.Goods
+	gold       int
+	reload     int
+	morale     int
+	daysAtSea  int
+	rations    RationsType
+	supplies   map[SupplyType]int
+	hungryDays int
+	moves      int
+	hardMoves  int
+	refits     map[RefitType]bool
+}
+
+func Create(c ClassType) *Ship {
+	s := &Ship{
+		class:      c,
+		hull:       ClassLookup[c].Hull,
+		sails:      MaxSails,
+		crew:       ClassLookup[c].Crew,
+		carpenters: max(1, ClassLookup[c].Crew/CrewPerCarpenter),
+		morale:     StartingMorale,
+		rations:    RationsFull,
+		supplies:   map[SupplyType]int{},
+		hold:       economy.Goods{},
+		refits:     map[RefitType]bool{},
+	}
+	for t := range SupplyLookup {
+		s.supplies[t] = s.GetSupplyCapacity(t)
+	}
+	return s
+}
+
+// RandomClass picks a ship class, larger ships being rarer
+func RandomClass() ClassType {
+	total := 0
+	for _, c := range ClassLookup {
+		total += c.rarity
+	}
+	pick := rand.Intn(total)
+	for t := ClassType(ClassSloop); t <= ClassManOfWar; t++ {
+		pick -= ClassLookup[t].rarity
+		if pick < 0 {
+			return t
+		}
+	}
+	return ClassSloop
+}
+
+// GetClass the stats of the ship's class, as changed by its refits
+func (s *Ship) GetClass() Class {
+	c := ClassLookup[s.class]
+	if len(s.refits) == 0 {
+		return c
+	}
+	for _, r := range refitOrder {
+		if s.refits[r] {
+			c = RefitLookup[r].apply(c)
+		}
+	}
+	return c
+}
+
+func (s *Ship) GetClassType() ClassType {
+	return s.class
+}
+
+func (s *Ship) GetHull() int {
+	return s.hull
+}
+
+func (s *Ship) GetCrew() int {
+	return s.crew
+}
+
+// GetGunsManned number of cannons the current crew is able to fire, with the powder left
+func (s *Ship) GetGunsManned() int {
+	return min(s.GetClass().Cannons, s.crew/CrewPerCannon, s.supplies[SupplyPowder])
+}
+
+func (s *Ship) Damage(d int) {
+	s.hull = max(0, s.hull-d)
+}
+
+func (s *Ship) GetSails() int {
+	return s.sails
+}
+
+func (s *Ship) DamageSails(d int) {
+	s.sails = max(0, s.sails-d)
+}
+
+// GetSpeed tiles per tick the ship sails, slowed down by torn sails and an unwilling crew
+func (s *Ship) GetSpeed() float64 {
+	return float64(s.GetClass().Speed) / SpeedScale * float64(s.sails) / MaxSails * float64(s.GetEffort()) / 100
+}
+
+// Ground a move through shallow water may scrape the hull, the deeper the draft the likelier,
+// returns the damage done
+func (s *Ship) Ground() int {
+	if rand.Intn(100) >= AgroundChance*(s.GetClass().Draft-1) {
+		return 0
+	}
+	d := rand.Intn(3) + 1
+	s.Damage(d)
+	return d
+}
+
+// GetRepairsNeeded hull and sail points missing
+func (s *Ship) GetRepairsNeeded() int {
+	return s.GetClass().Hull - s.hull + MaxSails - s.sails
+}
+
+// Repair patches up to n points, hull first and then sails, returns the points repaired
+func (s *Ship) Repair(n int) int {
+	if s.IsSunk() {
+		return 0
+	}
+	hull := min(n, s.GetClass().Hull-s.hull)
+	s.hull += hull
+	sails := min(n-hull, MaxSails-s.sails)
+	s.sails += sails
+	return hull + sails
+}
+
+func (s *Ship) GetCarpenters() int {
+	return min(s.carpenters, s.crew)
+}
+
+// RepairAtSea the carpenters' daily work, returns the points repaired
+func (s *Ship) RepairAtSea() int {
+	return s.Repair(s.GetCarpenters() * CarpenterRepairs)
+}
+
+func (s *Ship) LoseCrew(n int) {
+	s.crew = max(0, s.crew-n)
+	s.carpenters = min(s.carpenters, s.crew)
+}
+
+// AddCrew takes on up to n crew, returns how many found room aboard. The new hands bring their
+// own spirits with them, lifting or dragging down the crew's morale.
+func (s *Ship) AddCrew(n int) int {
+	return s.Recruit(n, StartingMorale)
+}
+
+// Recruit takes on up to n crew in the given spirits, returns how many found room aboard
+func (s *Ship) Recruit(n int, morale int) int {
+	added := max(0, min(n, s.GetClass().Crew-s.crew))
+	if added > 0 {
+		s.morale = (s.morale*s.crew + morale*added) / (s.crew + added)
+	}
+	s.crew += added
+	return added
+}
+
+// GetCargo units of cargo in the hold
+func (s *Ship) GetCargo() int {
+	return s.hold.Total()
+}
+
+// GetHold the commodities stowed in the hold
+func (s *Ship) GetHold() economy.Goods {
+	return s.hold
+}
+
+// LoadCargo stows up to n units of the commodity, returns how many fit in the hold
+func (s *Ship) LoadCargo(c economy.CommodityType, n int) int {
+	loaded := max(0, min(n, s.GetClass().Cargo-s.GetCargo()))
+	s.hold[c] += loaded
+	return loaded
+}
+
+// UnloadCargo removes up to n units of the commodity, returns how many were unloaded
+func (s *Ship) UnloadCargo(c economy.CommodityType, n int) int {
+	unloaded := max(0, min(n, s.hold[c]))
+	s.hold[c] -= unloaded
+	return unloaded
+}
+
+// LoadGoods stows as much of the goods as fits in the hold, returns what was loaded
+func (s *Ship) LoadGoods(g economy.Goods) economy.Goods {
+	loaded := economy.Goods{}
+	for _, c := range economy.Commodities() {
+		if n := s.LoadCargo(c, g[c]); n > 0 {
+			loaded[c] = n
+		}
+	}
+	return loaded
+}
+
+// GetGold the coin kept in the ship's strongbox
+func (s *Ship) GetGold() int {
+	return s.gold
+}
+
+func (s *Ship) AddGold(n int) {
+	s.gold += n
+}
+
+func (s *Ship) SpendGold(n int) {
+	s.gold -= n
+}
+
+// TakeGold empties the strongbox
+func (s *Ship) TakeGold() int {
+	g := s.gold
+	s.gold = 0
+	return g
+}
+
+func (s *Ship) IsSunk() bool {
+	return s.hull <= 0
+}
+
+func (s *Ship) IsLoaded() bool {
+	return s.reload <= 0
+}
+
+func (s *Ship) Reload() {
+	s.reload = ReloadTicks
+}
+
+// Tick advances time aboard by one game tick
+func (s *Ship) Tick() {
+	if s.reload > 0 {
+		s.reload--
+	}
+}
@@ -0,0 +1,93 @@
+package ship
+
+import "testing"
+
+func TestRepair(t *testing.T) {
+	s := Create(ClassSloop)
+	s.Damage(10)
+	s.DamageSails(20)
+	if s.GetRepairsNeeded() != 30 {
+		t.Fatalf("expected 30 points of repairs needed, got %d", s.GetRepairsNeeded())
+	}
+	if r := s.Repair(15); r != 15 || s.GetHull() != s.GetClass().Hull || s.GetSails() != MaxSails-15 {
+		t.Fatalf("expected hull to be repaired before sails, repaired %d hull %d sails %d", r, s.GetHull(), s.GetSails())
+	}
+	if r := s.RepairAtSea(); r != CarpenterRepairs {
+		t.Fatalf("expected a single carpenter to repair %d, got %d", CarpenterRepairs, r)
+	}
+	s.Damage(s.GetHull())
+	if r := s.Repair(10); r != 0 {
+		t.Fatalf("a sunk ship can't be repaired")
+	}
+}
+
+func TestMorale(t *testing.T) {
+	s := Create(ClassSloop)
+	speed := s.GetSpeed()
+	for s.GetMorale() >= LowMorale {
+		s.NewDay(false, false)
+	}
+	if s.GetEffort() >= 100 || s.GetSpeed() >= speed {
+		t.Fatalf("a grumbling crew should sail slower, effort %d speed %v", s.GetEffort(), s.GetSpeed())
+	}
+	s.NewDay(true, true)
+	if s.GetDaysAtSea() != 0 {
+		t.Fatalf("days at sea should be reset in port")
+	}
+	s.ChangeMorale(-MaxMorale)
+	if !s.Mutiny() {
+		t.Fatalf("a crew without any morale left always takes the ship")
+	}
+}
+
+func TestSupplies(t *testing.T) {
+	s := Create(ClassSloop)
+	if s.GetSupplyDays() != StoreDays {
+		t.Fatalf("a new ship should be fully stocked for %d days, got %d", StoreDays, s.GetSupplyDays())
+	}
+	food := s.GetSupply(SupplyFood)
+	s.Sail(true)
+	s.NewDay(true, false)
+	use := s.GetDailyUse(SupplyFood)
+	if s.GetSupply(SupplyFood) != food-use-use*HardSailingExtra/100 {
+		t.Fatalf("expected a day of hard sailing to use %d food, used %d", use+use*HardSailingExtra/100, food-s.GetSupply(SupplyFood))
+	}
+	s.supplies[SupplyWater] = 0
+	crew := s.GetCrew()
+	for i := 0; i <= StarvationDays; i++ {
+		s.NewDay(true, false)
+	}
+	if s.GetHungryDays() <= StarvationDays || s.GetCrew() >= crew {
+		t.Fatalf("a crew without water should fall sick, hungry for %d days, crew %d", s.GetHungryDays(), s.GetCrew())
+	}
+	if s.UsePowder(s.GetSupplyCapacity(SupplyPowder)+1) != s.GetSupplyCapacity(SupplyPowder) || s.GetGunsManned() != 0 {
+		t.Fatalf("cannons can't be fired without powder")
+	}
+}
+
+func TestRefit(t *testing.T) {
+	s := Create(ClassBrigantine)
+	class := s.GetClass()
+	value := s.GetValue()
+	s.AddRefit(RefitCannons)
+	s.AddRefit(RefitCopper)
+	c := s.GetClass()
+	if c.Cannons != class.Cannons*3/2 || c.Hull != class.Hull*5/4 || c.Speed != class.Speed+1 || c.Cargo >= class.Cargo {
+		t.Fatalf("refits should change the ship's stats, got %+v", c)
+	}
+	if s.GetHull() != c.Hull || len(s.GetRefitMarks()) != 2 || s.GetValue() <= value {
+		t.Fatalf("refitted ship should be sound, marked and worth more")
+	}
+
+	s.LoadCargo(1, c.Cargo)
+	bigger := Create(ClassGalleon)
+	bigger.Transfer(s)
+	if bigger.GetCrew() != s.GetCrew() || bigger.GetCargo() != s.GetCargo() || bigger.GetMorale() != s.GetMorale() {
+		t.Fatalf("the crew and cargo should move to the new ship")
+	}
+	smaller := Create(ClassSloop)
+	smaller.Transfer(bigger)
+	if smaller.GetCrew() != smaller.GetClass().Crew || smaller.GetCargo() != smaller.GetClass().Cargo {
+		t.Fatalf("only as many crew and cargo as there's room for should move to a smaller ship")
+	}
+}
@@ -0,0 +1,127 @@
+package ship
+
+// StoreDays days of food and water a ship's stores hold for a full crew
+const StoreDays = 30
+
+// PowderPerCannon shots of powder the magazine holds for each cannon
+const PowderPerCannon = 10
+
+// HardSailingExtra percentage more food and water used up by a day spent beating against the
+// wind or weathering storms
+const HardSailingExtra = 50
+
+// HungerMorale morale lost each day without enough food or water
+const HungerMorale = 10
+
+// StarvationDays days without enough food or water before the crew starts falling sick
+const StarvationDays = 3
+
+// SicknessLoss percentage of the crew lost each day once starving
+const SicknessLoss = 5
+
+const (
+	SupplyFood   = 1
+	SupplyWater  = 2
+	SupplyPowder = 3
+)
+
+type SupplyType int
+
+// Supply stores kept aboard, food and water units each last a share of the crew a day, and the
+// Price is in gold per unit
+type Supply struct {
+	Name  string
+	Price int
+}
+
+var SupplyLookup = map[SupplyType]Supply{
+	SupplyFood:   {Name: "Food", Price: 2},
+	SupplyWater:  {Name: "Water", Price: 1},
+	SupplyPowder: {Name: "Powder", Price: 3},
+}
+
+// shares a crew of n is divided in, for pay and provisions
+func shares(n int) int {
+	return (n + CrewPerShare - 1) / CrewPerShare
+}
+
+func (s *Ship) GetSupply(t SupplyType) int {
+	return s.supplies[t]
+}
+
+// GetSupplyCapacity how much of the supply the ship can stow
+func (s *Ship) GetSupplyCapacity(t SupplyType) int {
+	if t == SupplyPowder {
+		return s.GetClass().Cannons * PowderPerCannon
+	}
+	return shares(s.GetClass().Crew) * StoreDays
+}
+
+// LoadSupply stows up to n units, returns how many found room
+func (s *Ship) LoadSupply(t SupplyType, n int) int {
+	loaded := max(0, min(n, s.GetSupplyCapacity(t)-s.supplies[t]))
+	s.supplies[t] += loaded
+	return loaded
+}
+
+// UsePowder takes up to n shots of powder from the magazine, returns how many there were
+func (s *Ship) UsePowder(n int) int {
+	used := min(n, s.supplies[SupplyPowder])
+	s.supplies[SupplyPowder] -= used
+	return used
+}
+
+// GetDailyUse units of the supply the crew gets through on an ordinary day at sea
+func (s *Ship) GetDailyUse(t SupplyType) int {
+	switch t {
+	case SupplyFood:
+		return (shares(s.crew)*RationsLookup[s.rations].Food + 99) / 100
+	case SupplyWater:
+		return shares(s.crew)
+	}
+	return 0
+}
+
+// GetSupplyDays days the food and water will last at the current rate
+func (s *Ship) GetSupplyDays() int {
+	days := StoreDays
+	for _, t := range []SupplyType{SupplyFood, SupplyWater} {
+		if use := s.GetDailyUse(t); use > 0 {
+			days = min(days, s.supplies[t]/use)
+		}
+	}
+	return days
+}
+
+// GetHungryDays days in a row the crew has gone short of food or water
+func (s *Ship) GetHungryDays() int {
+	return s.hungryDays
+}
+
+// Sail records a move, hard ones being against the wind or through a storm
+func (s *Ship) Sail(hard bool) {
+	s.moves++
+	if hard {
+		s.hardMoves++
+	}
+}
+
+// consume the day's food and water, more of it after hard sailing, returns false if there
+// wasn't enough
+func (s *Ship) consume() bool {
+	extra := 0
+	if s.moves > 0 {
+		extra = HardSailingExtra * s.hardMoves / s.moves
+	}
+	s.moves, s.hardMoves = 0, 0
+	enough := true
+	for _, t := range []SupplyType{SupplyFood, SupplyWater} {
+		use := s.GetDailyUse(t)
+		use += use * extra / 100
+		if use > s.supplies[t] {
+			enough = false
+		}
+		s.supplies[t] = max(0, s.supplies[t]-use)
+	}
+	return enough
+}
@@ -0,0 +1,234 @@
+package spatial
+
+import (
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/window"
+	"sort"
+)
+
+// BucketSize width and height, in tiles, of each grid bucket
+const BucketSize = 16
+
+var bucketCols = (common.WorldCols + BucketSize - 1) / BucketSize
+var bucketRows = (common.WorldRows + BucketSize - 1) / BucketSize
+
+// Kind of entity stored in the index, so queries can ask only for what they care about
+type Kind int
+
+const (
+	KindPlayer Kind = iota
+	KindNpc
+	KindTown
+	KindWreck
+	KindFort
+	KindHideout
+	KindParty
+)
+
+// Item anything with an identity and a position on the world map
+type Item interface {
+	GetID() string
+	GetPos() common.Coordinates
+}
+
+type entry struct {
+	item   Item
+	kind   Kind
+	pos    common.Coordinates
+	bucket int
+}
+
+// Index grid-bucket index of entities on the world map
+type Index struct {
+	buckets [][]*entry
+	entries map[string]*entry
+}
+
+func New() *Index {
+	return &Index{
+		buckets: make([][]*entry, bucketCols*bucketRows),
+		entries: map[string]*entry{},
+	}
+}
+
+func bucketKey(c common.Coordinates) int {
+	bx := clamp(c.X/BucketSize, 0, bucketCols-1)
+	by := clamp(c.Y/BucketSize, 0, bucketRows-1)
+	return bx*bucketRows + by
+}
+
+func clamp(v, low, high int) int {
+	if v < low {
+		return low
+	} else if v > high {
+		return high
+	}
+	return v
+}
+
+// Insert adds an item to the index at its current position, replacing any item with the same ID
+func (idx *Index) Insert(item Item, kind Kind) {
+	idx.Remove(item.GetID())
+	e := &entry{item: item, kind: kind, pos: item.GetPos()}
+	e.bucket = bucketKey(e.pos)
+	idx.entries[item.GetID()] = e
+	idx.buckets[e.bucket] = append(idx.buckets[e.bucket], e)
+}
+
+func (idx *Index) Remove(id string) {
+	e, ok := idx.entries[id]
+	if !ok {
+		return
+	}
+	idx.removeFromBucket(e)
+	delete(idx.entries, id)
+}
+
+// Move updates the position of an indexed item, unknown IDs are ignored
+func (idx *Index) Move(id string, c common.Coordinates) {
+	e, ok := idx.entries[id]
+	if !ok {
+		return
+	}
+	e.pos = c
+	b := bucketKey(c)
+	if b != e.bucket {
+		idx.removeFromBucket(e)
+		e.bucket = b
+		idx.buckets[b] = append(idx.buckets[b], e)
+	}
+}
+
+func (idx *Index) removeFromBucket(e *entry) {
+	bucket := idx.buckets[e.bucket]
+	for i, o := range bucket {
+		if o == e {
+			bucket[i] = bucket[len(bucket)-1]
+			idx.buckets[e.bucket] = bucket[:len(bucket)-1]
+			return
+		}
+	}
+}
+
+func (idx *Index) Len() int {
+	return len(idx.entries)
+}
+
+// QueryRect all items of the given kinds (any kind if none given) within the region, sorted by position
+func (idx *Index) QueryRect(r window.Region, kinds ...Kind) []Item {
+	found := []*entry{}
+	idx.scan(r.X, r.Y, r.X+r.Cols, r.Y+r.Rows, kinds, func(e *entry) {
+		if r.IsPositionWithin(e.pos) {
+			found = append(found, e)
+		}
+	})
+	sortByPosition(found)
+	return items(found)
+}
+
+// QueryRadius all items of the given kinds within radius tiles of c, sorted by position
+func (idx *Index) QueryRadius(c common.Coordinates, radius int, kinds ...Kind) []Item {
+	found := []*entry{}
+	idx.scan(c.X-radius, c.Y-radius, c.X+radius, c.Y+radius, kinds, func(e *entry) {
+		if common.Distance(c, e.pos) <= radius {
+			found = append(found, e)
+		}
+	})
+	sortByPosition(found)
+	return items(found)
+}
+
+// Nearest up to k items of the given kinds closest to c, nearest first
+func (idx *Index) Nearest(c common.Coordinates, k int, kinds ...Kind) []Item {
+	if k <= 0 {
+		return []Item{}
+	}
+	found := []*entry{}
+	seen := map[*entry]bool{}
+	// search growing squares around c until the k-th closest can't be beaten by anything further out
+	for reach := BucketSize; ; reach += BucketSize {
+		idx.scan(c.X-reach, c.Y-reach, c.X+reach, c.Y+reach, kinds, func(e *entry) {
+			if !seen[e] {
+				seen[e] = true
+				found = append(found, e)
+			}
+		})
+		sort.Slice(found, func(i, j int) bool {
+			di, dj := common.Distance(c, found[i].pos), common.Distance(c, found[j].pos)
+			if di != dj {
+				return di < dj
+			}
+			return found[i].item.GetID() < found[j].item.GetID()
+		})
+		if len(found) >= k && common.Distance(c, found[k-1].pos) <= reach {
+			break
+		} else if reach > common.WorldCols && reach > common.WorldRows {
+			break
+		}
+	}
+	if len(found) > k {
+		found = found[:k]
+	}
+	return items(found)
+}
+
+// ByDistance sorts items, such as those found by QueryRadius, nearest to c first
+func ByDistance(c common.Coordinates, list []Item) []Item {
+	sort.Slice(list, func(i, j int) bool {
+		di, dj := common.Distance(c, list[i].GetPos()), common.Distance(c, list[j].GetPos())
+		if di != dj {
+			return di < dj
+		}
+		return list[i].GetID() < list[j].GetID()
+	})
+	return list
+}
+
+// scan calls fn for every entry of the given kinds in buckets overlapping the tile rectangle
+func (idx *Index) scan(x1, y1, x2, y2 int, kinds []Kind, fn func(e *entry)) {
+	bx1 := clamp(x1/BucketSize, 0, bucketCols-1)
+	by1 := clamp(y1/BucketSize, 0, bucketRows-1)
+	bx2 := clamp(x2/BucketSize, 0, bucketCols-1)
+	by2 := clamp(y2/BucketSize, 0, bucketRows-1)
+	for bx := bx1; bx <= bx2; bx++ {
+		for by := by1; by <= by2; by++ {
+			for _, e := range idx.buckets[bx*bucketRows+by] {
+				if matchesKind(e.kind, kinds) {
+					fn(e)
+				}
+			}
+		}
+	}
+}
+
+func matchesKind(k Kind, kinds []Kind) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, o := range kinds {
+		if o == k {
+			return true
+		}
+	}
+	return false
+}
+
+func sortByPosition(found []*entry) {
+	sort.Slice(found, func(i, j int) bool {
+		a, b := found[i].pos, found[j].pos
+		if a.X != b.X {
+			return a.X < b.X
+		} else if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return found[i].item.GetID() < found[j].item.GetID()
+	})
+}
+
+func items(found []*entry) []Item {
+	list := make([]Item, len(found))
+	for i, e := range found {
+		list[i] = e.item
+	}
+	return list
+}
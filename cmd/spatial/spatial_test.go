@@ -0,0 +1,88 @@
+package spatial
+
+import (
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/window"
+	"testing"
+)
+
+type ItemMock struct {
+	id  string
+	pos common.Coordinates
+}
+
+func (i *ItemMock) GetID() string              { return i.id }
+func (i *ItemMock) GetPos() common.Coordinates { return i.pos }
+
+func ids(items []Item) []string {
+	list := []string{}
+	for _, i := range items {
+		list = append(list, i.GetID())
+	}
+	return list
+}
+
+func TestQueryRectSameColumn(t *testing.T) {
+	idx := New()
+	idx.Insert(&ItemMock{id: "a", pos: common.Coordinates{X: 10, Y: 12}}, KindNpc)
+	idx.Insert(&ItemMock{id: "b", pos: common.Coordinates{X: 10, Y: 11}}, KindNpc)
+	idx.Insert(&ItemMock{id: "c", pos: common.Coordinates{X: 40, Y: 40}}, KindNpc)
+	idx.Insert(&ItemMock{id: "t", pos: common.Coordinates{X: 12, Y: 12}}, KindTown)
+
+	found := ids(idx.QueryRect(window.Region{X: 0, Y: 0, Cols: 20, Rows: 20}, KindNpc))
+	if len(found) != 2 || found[0] != "b" || found[1] != "a" {
+		t.Fatalf("expected [b a], got %v", found)
+	}
+	found = ids(idx.QueryRect(window.Region{X: 0, Y: 0, Cols: 20, Rows: 20}))
+	if len(found) != 3 {
+		t.Fatalf("expected all kinds to be returned, got %v", found)
+	}
+}
+
+func TestMove(t *testing.T) {
+	idx := New()
+	item := &ItemMock{id: "a", pos: common.Coordinates{X: 1, Y: 1}}
+	idx.Insert(item, KindNpc)
+	item.pos = common.Coordinates{X: 500, Y: 500}
+	idx.Move(item.id, item.pos)
+
+	if found := idx.QueryRadius(common.Coordinates{X: 1, Y: 1}, 5); len(found) != 0 {
+		t.Fatalf("item should have left its old bucket, got %v", ids(found))
+	}
+	if found := idx.QueryRadius(common.Coordinates{X: 498, Y: 503}, 3); len(found) != 1 {
+		t.Fatalf("item should be found at its new position, got %v", ids(found))
+	}
+	idx.Remove(item.id)
+	if idx.Len() != 0 {
+		t.Fatalf("item should have been removed")
+	}
+}
+
+func TestNearest(t *testing.T) {
+	idx := New()
+	idx.Insert(&ItemMock{id: "far", pos: common.Coordinates{X: 700, Y: 700}}, KindNpc)
+	idx.Insert(&ItemMock{id: "near", pos: common.Coordinates{X: 102, Y: 100}}, KindNpc)
+	idx.Insert(&ItemMock{id: "mid", pos: common.Coordinates{X: 80, Y: 130}}, KindNpc)
+	idx.Insert(&ItemMock{id: "player", pos: common.Coordinates{X: 100, Y: 100}}, KindPlayer)
+
+	found := ids(idx.Nearest(common.Coordinates{X: 100, Y: 100}, 3, KindNpc))
+	if len(found) != 3 || found[0] != "near" || found[1] != "mid" || found[2] != "far" {
+		t.Fatalf("expected [near mid far], got %v", found)
+	}
+	found = ids(idx.Nearest(common.Coordinates{X: 100, Y: 100}, 1, KindNpc))
+	if len(found) != 1 || found[0] != "near" {
+		t.Fatalf("expected [near], got %v", found)
+	}
+}
+
+func TestByDistance(t *testing.T) {
+	idx := New()
+	idx.Insert(&ItemMock{id: "a", pos: common.Coordinates{X: 95, Y: 100}}, KindNpc)
+	idx.Insert(&ItemMock{id: "b", pos: common.Coordinates{X: 101, Y: 101}}, KindNpc)
+	idx.Insert(&ItemMock{id: "c", pos: common.Coordinates{X: 98, Y: 104}}, KindNpc)
+
+	found := ids(ByDistance(common.Coordinates{X: 100, Y: 100}, idx.QueryRadius(common.Coordinates{X: 100, Y: 100}, 5, KindNpc)))
+	if len(found) != 3 || found[0] != "b" || found[1] != "c" || found[2] != "a" {
+		t.Fatalf("expected [b c a], got %v", found)
+	}
+}
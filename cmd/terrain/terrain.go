@@ -34,6 +34,7 @@ var TypeLookup = map[common.TerrainType]TypeQualities{
 	common.TerrainTypePeak:         {color: color.RGBA{229, 229, 229, 255}, tile: resources.GetTerrainTile(common.TerrainTypePeak), Passable: false, RequiresBoat: false},
 	common.TerrainTypeTown:         {color: color.RGBA{246, 104, 94, 255}, tile: resources.GetTerrainTile(common.TerrainTypeTown), Passable: true, RequiresBoat: false},
 	common.TerrainTypeGhostTown:    {color: color.RGBA{147, 62, 56, 255}, tile: resources.GetTerrainTile(common.TerrainTypeGhostTown), Passable: true, RequiresBoat: false},
+	common.TerrainTypeHideout:      {color: color.RGBA{40, 40, 40, 255}, tile: resources.GetTerrainTile(common.TerrainTypeHideout), Passable: true, RequiresBoat: false},
 }
 
 func GetColor(tt common.TerrainType) color.RGBA {
@@ -0,0 +1,137 @@
+package town
+
+import (
+	"image"
+	"image/color"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/resources"
+	"pirate-wars/cmd/window"
+)
+
+// FortCannonsPerTile cannons a fort mounts for each tile of the town it guards
+const FortCannonsPerTile = 3
+
+// FortGarrisonPerTile soldiers garrisoned for each tile of the town
+const FortGarrisonPerTile = 15
+
+// FortRange tiles the fort's cannons reach
+const FortRange = 6
+
+// FortWallsPerCannon damage the walls take before a cannon is dismounted
+const FortWallsPerCannon = 6
+
+// FortReloadTicks ticks the fort's gunners need to reload
+const FortReloadTicks = 6
+
+// FortRebuild wall points rebuilt and soldiers recruited each day
+const FortRebuild = 5
+
+// Fort guards a town's waters, firing upon the ships of its enemies
+type Fort struct {
+	id       string
+	pos      common.Coordinates
+	size     int
+	walls    int
+	garrison int
+	reload   int
+}
+
+// CreateFort a fort of the given size, in town tiles, guarding pos
+func CreateFort(id string, pos common.Coordinates, size int) *Fort {
+	f := Fort{id: "fort-" + id, pos: pos, size: size}
+	f.walls = f.GetMaxCannons() * FortWallsPerCannon
+	f.garrison = f.GetMaxGarrison()
+	return &f
+}
+
+func (f *Fort) GetID() string {
+	return f.id
+}
+
+func (f *Fort) GetPos() common.Coordinates {
+	return f.pos
+}
+
+func (f *Fort) GetPreviousPos() common.Coordinates {
+	return f.pos
+}
+
+func (f *Fort) GetTileImage() image.Image {
+	return resources.GetFortTile()
+}
+
+func (f *Fort) GetViewableRange() window.Dimensions {
+	return window.Dimensions{Width: FortRange, Height: FortRange}
+}
+
+func (f *Fort) IsHighlighted() bool {
+	return false
+}
+
+func (f *Fort) GetColor() color.Color {
+	return color.RGBA{150, 150, 150, 255}
+}
+
+// GetMaxCannons cannons the fort mounts at full strength, the more tiles it guards the more it mounts
+func (f *Fort) GetMaxCannons() int {
+	return f.size * FortCannonsPerTile
+}
+
+func (f *Fort) GetMaxGarrison() int {
+	return f.size * FortGarrisonPerTile
+}
+
+// GetCannons cannons still mounted on what's left of the walls
+func (f *Fort) GetCannons() int {
+	return min(f.GetMaxCannons(), (f.walls+FortWallsPerCannon-1)/FortWallsPerCannon)
+}
+
+func (f *Fort) GetGarrison() int {
+	return f.garrison
+}
+
+func (f *Fort) GetRange() int {
+	return FortRange
+}
+
+// Bombard the walls crumble under cannon fire, and some of the garrison fall
+func (f *Fort) Bombard(damage int, casualties int) {
+	f.walls = max(0, f.walls-damage)
+	f.garrison = max(0, f.garrison-casualties)
+}
+
+func (f *Fort) IsLoaded() bool {
+	return f.reload <= 0
+}
+
+func (f *Fort) Reload() {
+	f.reload = FortReloadTicks
+}
+
+func (f *Fort) Tick() {
+	if f.reload > 0 {
+		f.reload--
+	}
+}
+
+// resize the fort is extended or left to ruin as the town it guards grows or shrinks
+func (f *Fort) resize(size int) {
+	f.size = size
+	f.walls = min(f.walls, f.GetMaxCannons()*FortWallsPerCannon)
+	f.garrison = min(f.garrison, f.GetMaxGarrison())
+}
+
+// Extend the fort is enlarged to size, the damage it's taken still to be made good
+func (f *Fort) Extend(size int) {
+	damage := f.GetMaxCannons()*FortWallsPerCannon - f.walls
+	casualties := f.GetMaxGarrison() - f.garrison
+	f.size = size
+	f.walls = max(0, f.GetMaxCannons()*FortWallsPerCannon-damage)
+	f.garrison = max(0, f.GetMaxGarrison()-casualties)
+}
+
+// NewDay the walls are rebuilt and fresh soldiers join the garrison
+func (f *Fort) NewDay() {
+	f.walls = min(f.GetMaxCannons()*FortWallsPerCannon, f.walls+FortRebuild)
+	f.garrison = min(f.GetMaxGarrison(), f.garrison+FortRebuild)
+}
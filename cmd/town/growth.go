@@ -0,0 +1,141 @@
+package town
+
+import (
+	"fmt"
+	"math/rand"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/economy"
+	"pirate-wars/cmd/world"
+)
+
+// MaxProsperity how far a town's fortunes can swing either way
+const MaxProsperity = 20
+
+// TradePerProsperity units bought and sold at the market in a day that make the town more prosperous
+const TradePerProsperity = 10
+
+// ShortageHardship prosperity lost each day for every needed commodity the market has run out of
+const ShortageHardship = 2
+
+// PiracyHardship prosperity lost for every ship taken or sunk in the town's waters
+const PiracyHardship = 5
+
+// PiracyRange tiles from a town within which piracy scares off its trade
+const PiracyRange = 12
+
+// CrowdedTile people on each tile at which a town spreads onto another
+const CrowdedTile = PeoplePerTile * 3 / 2
+
+// SparseTile people on each tile below which a town gives one up
+const SparseTile = PeoplePerTile / 2
+
+// AbandonPopulation people below which the last of the townsfolk leave
+const AbandonPopulation = 50
+
+// DisasterChance daily percentage chance of a disaster striking a town
+const DisasterChance = 1
+
+// DisasterLoss percentage of the people lost to a disaster
+const DisasterLoss = 30
+
+// ResettleChance daily percentage chance of settlers moving into a ghost town
+const ResettleChance = 2
+
+var disasters = []string{"Fire ravages", "Fever sweeps through", "A hurricane levels", "An earthquake shakes"}
+
+// prosper trade makes the town prosperous, while shortages, piracy and disasters make it suffer.
+// People move to a prosperous town and leave a suffering one, and the town grows and shrinks with
+// them, returns the news if anything noteworthy happened.
+func (t *Town) prosper(world *world.MapView) string {
+	change := t.market.GetTraded()/TradePerProsperity - t.market.GetShortages()*ShortageHardship - t.piracy*PiracyHardship - 1
+	t.prosperity = min(MaxProsperity, max(-MaxProsperity, t.prosperity+change))
+	t.piracy = 0
+	t.population += t.population * t.prosperity / 1000
+	news := ""
+	if rand.Intn(100) < DisasterChance {
+		t.population -= t.population * DisasterLoss / 100
+		t.prosperity = -MaxProsperity / 2
+		news = fmt.Sprintf("%s %s", disasters[rand.Intn(len(disasters))], t.name)
+	}
+	if t.population < AbandonPopulation {
+		t.MakeGhostTown(world)
+		return fmt.Sprintf("%s has been abandoned", t.name)
+	}
+	if t.population > len(t.pos)*CrowdedTile {
+		t.spread(world)
+	} else if t.population < len(t.pos)*SparseTile && len(t.pos) > 1 {
+		t.shrink(world)
+	}
+	return news
+}
+
+// spread the town grows onto the coast next to any of its tiles
+func (t *Town) spread(world *world.MapView) {
+	for _, c := range t.pos {
+		if t.growFrom(c, world) {
+			t.fort.resize(len(t.pos))
+			return
+		}
+	}
+}
+
+// shrink the town's newest tile returns to the wild, the founding tile is kept to the last so
+// the heatmaps leading to it stay good
+func (t *Town) shrink(world *world.MapView) {
+	last := len(t.pos) - 1
+	world.SetPositionType(t.pos[last], t.ground[last])
+	t.pos = t.pos[:last]
+	t.ground = t.ground[:last]
+	t.fort.resize(len(t.pos))
+}
+
+// resettle settlers of a nation move into the ruins, rebuilding from the founding tile
+func (t *Town) resettle(world *world.MapView) {
+	for i, c := range t.pos[1:] {
+		world.SetPositionType(c, t.ground[i+1])
+	}
+	t.pos = t.pos[:1]
+	t.ground = t.ground[:1]
+	world.SetPositionType(t.GetPos(), common.TerrainTypeTown)
+	t.SetTerrainType(common.TerrainTypeTown)
+	t.population = PeoplePerTile
+	t.flag = common.GetRandomFlag()
+	t.market = economy.CreateMarket()
+	t.stockShipyard()
+	t.hands = 0
+	t.fort.resize(1)
+	t.logger.Info(fmt.Sprintf("[%v] Town %s resettled by %s", t.id, t.name, t.flag.Name))
+}
+
+func (t *Town) GetProsperity() int {
+	return t.prosperity
+}
+
+// ReportPiracy a ship was taken or sunk at c, scaring trade away from the nearest town
+func (ts *Towns) ReportPiracy(c common.Coordinates) {
+	var nearest *Town
+	for i := range ts.list {
+		t := &ts.list[i]
+		d := common.Distance(c, t.GetPos())
+		if !t.IsGhostTown() && d <= PiracyRange && (nearest == nil || d < common.Distance(c, nearest.GetPos())) {
+			nearest = t
+		}
+	}
+	if nearest != nil {
+		nearest.piracy++
+	}
+}
+
+// DigChannel turns the land at c into shallow water, and brings the heatmaps of every town it
+// opens a shorter way to up to date. Returns how many towns' heatmaps changed.
+func (ts *Towns) DigChannel(c common.Coordinates, world *world.MapView) int {
+	world.SetPositionType(c, common.TerrainTypeShallowWater)
+	updated := 0
+	for i := range ts.list {
+		if ts.list[i].updateHeatMap(c, world) {
+			updated++
+		}
+	}
+	ts.logger.Infof("Channel dug at %v, %d town heatmaps updated", c, updated)
+	return updated
+}
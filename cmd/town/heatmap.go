@@ -48,17 +48,8 @@ func (town *Town) generateHeatMap(world *world.MapView) bool {
 		//t.Logger.Infof("[towm %v] Processing %v, %v", t`own, x, y)
 		if world.IsPassableByBoat(c) {
 			//t.Logger.Debug(fmt.Sprintf("[town %v] Assigning cost %v, %v = %v [%v]", town, x, y, cost, t.Towns[town].HeatMap[x][y]))
-			if world.GetPositionType(c) == common.TerrainTypeShallowWater {
-				// shallow water costs more (dangerous)
-				cost = cost + 10
-				town.HeatMap.SetCost(c, cost)
-			} else if world.GetPositionType(c) == common.TerrainTypeOpenWater {
-				// open water faster than shallow, but not as fast as deep
-				cost = cost + 5
-				town.HeatMap.SetCost(c, cost)
-			} else {
-				town.HeatMap.SetCost(c, cost)
-			}
+			cost = cost + waterCost(world, c)
+			town.HeatMap.SetCost(c, cost)
 			cost = cost + 1
 		} else {
 			if cost == 0 && world.GetPositionType(c) == common.TerrainTypeTown {
@@ -95,6 +86,66 @@ func (town *Town) generateHeatMap(world *world.MapView) bool {
 	}
 }
 
+// waterCost the extra cost of sailing through c, shallow water is dangerous and open water not as
+// fast as deep
+func waterCost(world *world.MapView, c common.Coordinates) HeatMapCost {
+	switch world.GetPositionType(c) {
+	case common.TerrainTypeShallowWater:
+		return 10
+	case common.TerrainTypeOpenWater:
+		return 5
+	}
+	return 0
+}
+
+// updateHeatMap works the costs outwards from c, which has just become navigable, lowering them
+// wherever the new water makes for a shorter way to the town. Returns whether any changed.
+func (town *Town) updateHeatMap(c common.Coordinates, world *world.MapView) bool {
+	// the cost of sailing on from a tile, the town itself is where every route ends
+	onward := func(from common.Coordinates) (HeatMapCost, bool) {
+		if common.CoordsMatch(from, town.GetPos()) {
+			return 0, true
+		}
+		cost := town.HeatMap.GetCost(from)
+		if cost < 0 || cost >= MaxMovementCost || !world.IsPassableByBoat(from) {
+			return 0, false
+		}
+		return cost + 1, true
+	}
+	best := MaxMovementCost
+	for _, dir := range common.Directions {
+		n := common.AddDirection(c, dir)
+		if !common.Inbounds(n) {
+			continue
+		}
+		if cost, ok := onward(n); ok {
+			best = min(best, cost+waterCost(world, c))
+		}
+	}
+	if best == MaxMovementCost {
+		return false
+	}
+	town.HeatMap.SetCost(c, best)
+	queue := []common.Coordinates{c}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		cost, _ := onward(cur)
+		for _, dir := range common.Directions {
+			n := common.AddDirection(cur, dir)
+			if !common.Inbounds(n) || !world.IsPassableByBoat(n) {
+				continue
+			}
+			candidate := cost + waterCost(world, n)
+			if old := town.HeatMap.GetCost(n); old < 0 || old > candidate {
+				town.HeatMap.SetCost(n, candidate)
+				queue = append(queue, n)
+			}
+		}
+	}
+	return true
+}
+
 //func (h *HeatMap) Paint(avatar npc.AvatarReadOnly, npcs []npc.AvatarReadOnly, highlight common.ViewableEntity) *fyne.Container {
 //	// center viewport on avatar
 //	v := window.GetViewport(avatar.GetPos(), window.ViewableArea)
@@ -0,0 +1,96 @@
+package town
+
+import (
+	"math/rand"
+	"pirate-wars/cmd/ship"
+)
+
+// ShipyardStock ships of each class a shipyard keeps for sale, of its nation's favourite one more
+const ShipyardStock = 2
+
+// ShipyardBuildChance daily percentage chance of a shipyard finishing a ship of each class it's
+// short of
+const ShipyardBuildChance = 10
+
+// FavouriteDiscount percentage off the ships a nation's shipwrights are best known for
+const FavouriteDiscount = 20
+
+// favouriteClass the ships each nation's shipwrights are best known for
+var favouriteClass = map[string]ship.ClassType{
+	"Pirate":  ship.ClassSloop,
+	"French":  ship.ClassFrigate,
+	"English": ship.ClassManOfWar,
+	"Dutch":   ship.ClassBrigantine,
+	"Spanish": ship.ClassGalleon,
+}
+
+// largestClass the largest ship a town of each size has the slipways to build
+var largestClass = map[PopulationClass]ship.ClassType{
+	PopulationHamlet:  ship.ClassSloop,
+	PopulationVillage: ship.ClassBrigantine,
+	PopulationTown:    ship.ClassFrigate,
+	PopulationCity:    ship.ClassManOfWar,
+}
+
+// sizeMarkup percentage added to the price of ships, the smaller the town the dearer they are
+var sizeMarkup = map[PopulationClass]int{
+	PopulationHamlet:  20,
+	PopulationVillage: 10,
+	PopulationTown:    0,
+	PopulationCity:    -10,
+}
+
+// GetShipsForSale the classes of ship the town's shipyard builds
+func (t *Town) GetShipsForSale() []ship.ClassType {
+	list := []ship.ClassType{}
+	for c := ship.ClassType(ship.ClassSloop); c <= largestClass[t.GetPopulationClass()]; c++ {
+		list = append(list, c)
+	}
+	return list
+}
+
+func (t *Town) GetShipStock(c ship.ClassType) int {
+	return t.shipyard[c]
+}
+
+// GetShipPrice what the shipyard asks for a new ship of the class
+func (t *Town) GetShipPrice(c ship.ClassType) int {
+	markup := sizeMarkup[t.GetPopulationClass()]
+	if favouriteClass[t.flag.Name] == c {
+		markup -= FavouriteDiscount
+	}
+	return ship.ClassLookup[c].Price * (100 + markup) / 100
+}
+
+// BuyShip takes a ship of the class from the shipyard's stock, returns false if there's none left
+func (t *Town) BuyShip(c ship.ClassType) bool {
+	if t.shipyard[c] == 0 {
+		return false
+	}
+	t.shipyard[c]--
+	return true
+}
+
+func (t *Town) maxShipStock(c ship.ClassType) int {
+	if favouriteClass[t.flag.Name] == c {
+		return ShipyardStock + 1
+	}
+	return ShipyardStock
+}
+
+// stockShipyard fills the slipways with as many ships as the shipyard keeps
+func (t *Town) stockShipyard() {
+	t.shipyard = map[ship.ClassType]int{}
+	for _, c := range t.GetShipsForSale() {
+		t.shipyard[c] = rand.Intn(t.maxShipStock(c) + 1)
+	}
+}
+
+// buildShips the shipwrights now and then finish a ship the shipyard is short of
+func (t *Town) buildShips() {
+	for _, c := range t.GetShipsForSale() {
+		if t.shipyard[c] < t.maxShipStock(c) && rand.Intn(100) < ShipyardBuildChance {
+			t.shipyard[c]++
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package town
+
+import "pirate-wars/cmd/ship"
+
+// PeoplePerHand townsfolk for every sailor looking for a berth in the town's tavern
+const PeoplePerHand = 20
+
+// HandsArriving percentage of the tavern's full complement of sailors turning up each day
+const HandsArriving = 10
+
+// GetHands sailors in the tavern looking for a berth
+func (t *Town) GetHands() int {
+	return t.hands
+}
+
+// GetHandsMorale the spirits the town's sailors would bring aboard, those of a prosperous town
+// are cheerful and those of a struggling one sullen
+func (t *Town) GetHandsMorale() int {
+	return min(ship.MaxMorale, max(ship.MutinyMorale, ship.StartingMorale+t.prosperity))
+}
+
+// HireHands up to n sailors sign on, returns how many there were
+func (t *Town) HireHands(n int) int {
+	hired := max(0, min(n, t.hands))
+	t.hands -= hired
+	return hired
+}
+
+func (t *Town) maxHands() int {
+	return t.population / PeoplePerHand
+}
+
+// gatherHands sailors drift into the tavern, the bigger the town the more of them
+func (t *Town) gatherHands() {
+	t.hands = min(t.maxHands(), t.hands+max(1, t.maxHands()*HandsArriving/100))
+}
@@ -7,31 +7,73 @@ import (
 	"image/color"
 	"math/rand"
 	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/economy"
 	"pirate-wars/cmd/resources"
+	"pirate-wars/cmd/ship"
+	"pirate-wars/cmd/spatial"
 	"pirate-wars/cmd/window"
 	"pirate-wars/cmd/world"
 
 	"go.uber.org/zap"
 )
 
+// PeoplePerTile rough number of people living on each of a town's tiles
+const PeoplePerTile = 250
+
+const (
+	PopulationHamlet  = 0
+	PopulationVillage = 1
+	PopulationTown    = 2
+	PopulationCity    = 3
+)
+
+type PopulationClass int
+
+// Population a size class of town, Min is the fewest people it takes to count as one
+type Population struct {
+	Name string
+	Min  int
+}
+
+var PopulationLookup = map[PopulationClass]Population{
+	PopulationHamlet:  {Name: "Hamlet", Min: 0},
+	PopulationVillage: {Name: "Village", Min: 500},
+	PopulationTown:    {Name: "Town", Min: 1000},
+	PopulationCity:    {Name: "City", Min: 2000},
+}
+
 type Towns struct {
 	logger *zap.SugaredLogger
 	list   []Town
+	names  map[string]bool
 }
 
 type Town struct {
-	id          string
+	*settlement
+	id        string
+	name      string
+	logger    *zap.SugaredLogger
+	color     color.Color
+	HeatMap   HeatMap
+	blink     bool
+	alternate bool
+	fort      *Fort
+}
+
+// settlement what changes as a town grows, declines and is resettled, shared by every copy of the town
+type settlement struct {
 	pos         []common.Coordinates
+	ground      []common.TerrainType
 	terrainType common.TerrainType
-	logger      *zap.SugaredLogger
-	color       color.Color
-	HeatMap     HeatMap
-	blink       bool
-	alternate   bool
+	population  int
+	prosperity  int
+	piracy      int
+	flag        common.Flag
+	market      *economy.Market
+	shipyard    map[ship.ClassType]int
+	hands       int
 }
 
-var townList []Town
-
 func (t *Town) GetID() string {
 	return t.id
 }
@@ -71,7 +113,27 @@ func (t *Town) SetTerrainType(tt common.TerrainType) {
 }
 
 func (t *Town) GetName() string {
-	return t.id
+	return t.name
+}
+
+// GetTiles every tile the town covers
+func (t *Town) GetTiles() []common.Coordinates {
+	return t.pos
+}
+
+func (t *Town) GetPopulation() int {
+	return t.population
+}
+
+// GetPopulationClass the largest size class the town's population reaches
+func (t *Town) GetPopulationClass() PopulationClass {
+	class := PopulationClass(PopulationHamlet)
+	for c, p := range PopulationLookup {
+		if t.population >= p.Min && c > class {
+			class = c
+		}
+	}
+	return class
 }
 
 func (t *Town) GetColor() color.Color {
@@ -90,7 +152,40 @@ func (t *Town) GetTileImage() image.Image {
 }
 
 func (t *Town) GetFlag() string {
-	return "NA"
+	return t.flag.Name
+}
+
+// GetMarket the town's market, shared by every copy of the town
+func (t *Town) GetMarket() *economy.Market {
+	return t.market
+}
+
+// GetFort the fort guarding the town's waters
+func (t *Town) GetFort() *Fort {
+	return t.fort
+}
+
+func (t *Town) GetDetails() string {
+	f := t.fort
+	return fmt.Sprintf("Size: %s\nPopulation: %d\nFort: %d/%d cannons\nGarrison: %d\nRange: %d\n", PopulationLookup[t.GetPopulationClass()].Name, t.population, f.GetCannons(), f.GetMaxCannons(), f.GetGarrison(), f.GetRange())
+}
+
+// GetSummary the town's size and nation, in a line
+func (t *Town) GetSummary() string {
+	if t.IsGhostTown() {
+		return "Abandoned"
+	}
+	return fmt.Sprintf("%s %s, %d people", t.flag.Name, PopulationLookup[t.GetPopulationClass()].Name, t.population)
+}
+
+// Covers whether c is one of the town's tiles
+func (t *Town) Covers(c common.Coordinates) bool {
+	for _, p := range t.pos {
+		if common.CoordsMatch(p, c) {
+			return true
+		}
+	}
+	return false
 }
 
 func (t *Town) AccessibleFrom(c common.Coordinates) bool {
@@ -105,10 +200,32 @@ func (t *Town) AccessibleFrom(c common.Coordinates) bool {
 
 func (t *Town) MakeGhostTown(world *world.MapView) {
 	t.logger.Info(fmt.Sprintf("[%v] Town turns to ghost town at %v", t.id, t.GetPos()))
+	t.SetTerrainType(common.TerrainTypeGhostTown)
 	for _, c := range t.pos {
-		t.SetTerrainType(common.TerrainTypeGhostTown)
 		world.SetPositionType(c, common.TerrainTypeGhostTown)
 	}
+	t.population = 0
+	t.prosperity = 0
+	t.fort.resize(0)
+}
+
+// IsGhostTown whether the town has been abandoned
+func (t *Town) IsGhostTown() bool {
+	return t.terrainType == common.TerrainTypeGhostTown
+}
+
+// growFrom turns a piece of coast next to c into a town tile, returns false if there's none left
+func (t *Town) growFrom(c common.Coordinates, world *world.MapView) bool {
+	for _, a := range world.GetAdjacentCoords(c) {
+		p := world.GetPositionType(a)
+		if (p == common.TerrainTypeLowland || p == common.TerrainTypeBeach) && world.IsAdjacentToWater(a) {
+			world.SetPositionType(a, common.TerrainTypeTown)
+			t.pos = append(t.pos, a)
+			t.ground = append(t.ground, p)
+			return true
+		}
+	}
+	return false
 }
 
 func (ts *Towns) CreateTown(c common.Coordinates, world *world.MapView) Town {
@@ -122,11 +239,17 @@ func (ts *Towns) CreateTown(c common.Coordinates, world *world.MapView) Town {
 	}
 
 	town := Town{
-		id:          common.GenID(c),
-		pos:         []common.Coordinates{c},
-		terrainType: common.TerrainTypeTown,
-		logger:      ts.logger,
-		color:       color.RGBA{189, 55, 31, 255},
+		settlement: &settlement{
+			pos:         []common.Coordinates{c},
+			ground:      []common.TerrainType{world.GetPositionType(c)},
+			terrainType: common.TerrainTypeTown,
+			flag:        common.GetRandomFlag(),
+			market:      economy.CreateMarket(),
+		},
+		id:     common.GenID(c),
+		name:   ts.uniqueName(),
+		logger: ts.logger,
+		color:  color.RGBA{189, 55, 31, 255},
 		HeatMap: HeatMap{
 			grid: heatMap,
 		},
@@ -136,20 +259,33 @@ func (ts *Towns) CreateTown(c common.Coordinates, world *world.MapView) Town {
 	heatMap[c.X][c.Y] = 0
 
 	// grow towns
-	for _, a := range world.GetAdjacentCoords(c) {
-		p := world.GetPositionType(a)
-		if (p == common.TerrainTypeLowland || p == common.TerrainTypeBeach) && world.IsAdjacentToWater(a) {
-			world.SetPositionType(a, common.TerrainTypeTown)
-			//HeatMap[a.X][a.Y] = 0
-			town.pos = append(town.pos, a)
-		}
+	for town.growFrom(c, world) {
 	}
+	town.population = len(town.pos) * PeoplePerTile * (50 + rand.Intn(101)) / 100
+	town.fort = CreateFort(town.id, c, len(town.pos))
+	town.stockShipyard()
+	town.hands = town.maxHands()
 	world.SetMapItem(&town)
 	return town
 }
 
+// uniqueName a place name no other town has taken yet
+func (ts *Towns) uniqueName() string {
+	name := common.GeneratePlaceName()
+	for i := 2; ts.names[name]; i++ {
+		name = common.GeneratePlaceName()
+		if i > 10 {
+			// the lists are running dry, tell the namesakes apart by number
+			name = fmt.Sprintf("%s %d", name, i)
+		}
+	}
+	ts.names[name] = true
+	return name
+}
+
 func (ts *Towns) initializeTowns(fn func() common.Coordinates, world *world.MapView) []Town {
 	ts.logger.Info(fmt.Sprintf("Initializing %v towns", common.TotalTowns))
+	townList := []Town{}
 	for i := 0; i < common.TotalTowns; i++ {
 		for {
 			c := fn()
@@ -160,11 +296,12 @@ func (ts *Towns) initializeTowns(fn func() common.Coordinates, world *world.MapV
 				if world.IsAdjacentToWater(c) {
 					town := ts.CreateTown(c, world)
 					if town.generateHeatMap(world) {
-						ts.logger.Info(fmt.Sprintf("[%v] Town created at %v", town.id, c))
+						ts.logger.Info(fmt.Sprintf("[%v] Town %s created at %v", town.id, town.name, c))
 						townList = append(townList, town)
 						break
 					} else {
 						town.MakeGhostTown(world)
+						delete(ts.names, town.name)
 					}
 				}
 			}
@@ -173,23 +310,69 @@ func (ts *Towns) initializeTowns(fn func() common.Coordinates, world *world.MapV
 	return townList
 }
 
-func Init(world *world.MapView, logger *zap.SugaredLogger) *Towns {
+func Init(world *world.MapView, index *spatial.Index, logger *zap.SugaredLogger) *Towns {
 	ts := Towns{
 		logger: logger,
 		list:   []Town{},
+		names:  map[string]bool{},
 	}
 	ts.list = ts.initializeTowns(common.RandomPosition, world)
+	for i := range ts.list {
+		index.Insert(&ts.list[i], spatial.KindTown)
+		index.Insert(ts.list[i].fort, spatial.KindFort)
+	}
 	ts.logger.Info(fmt.Sprintf("Created %v towns", len(ts.list)))
 	return &ts
 }
 
+// GetRandomTown any town still lived in
 func (ts *Towns) GetRandomTown() (Town, error) {
-	if len(ts.list) == 0 {
+	settled := []int{}
+	for i := range ts.list {
+		if !ts.list[i].IsGhostTown() {
+			settled = append(settled, i)
+		}
+	}
+	if len(settled) == 0 {
 		return Town{}, errors.New("no towns found")
 	}
-	return ts.list[rand.Intn(len(ts.list))], nil
+	return ts.list[settled[rand.Intn(len(settled))]], nil
 }
 
 func (ts *Towns) GetTowns() []Town {
 	return ts.list
 }
+
+// GetTown the town with the given ID, rather than a copy of it
+func (ts *Towns) GetTown(id string) (*Town, bool) {
+	for i := range ts.list {
+		if ts.list[i].id == id {
+			return &ts.list[i], true
+		}
+	}
+	return nil, false
+}
+
+// NewDay the forts are repaired and reinforced, the markets restocked, and the towns grow or
+// decline, returns the news of the day
+func (ts *Towns) NewDay(world *world.MapView) []string {
+	news := []string{}
+	for i := range ts.list {
+		t := &ts.list[i]
+		if t.IsGhostTown() {
+			if rand.Intn(100) < ResettleChance {
+				t.resettle(world)
+				news = append(news, fmt.Sprintf("Settlers from %s have moved into the ruins of %s", t.GetFlag(), t.name))
+			}
+			continue
+		}
+		if n := t.prosper(world); n != "" {
+			news = append(news, n)
+		}
+		t.fort.NewDay()
+		t.market.NewDay()
+		t.buildShips()
+		t.gatherHands()
+	}
+	return news
+}
@@ -0,0 +1,188 @@
+package town
+
+import (
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/ship"
+	"pirate-wars/cmd/spatial"
+	"pirate-wars/cmd/world"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"go.uber.org/zap"
+)
+
+func TestPopulationClass(t *testing.T) {
+	cases := map[int]PopulationClass{
+		120:  PopulationHamlet,
+		500:  PopulationVillage,
+		1999: PopulationTown,
+		3000: PopulationCity,
+	}
+	for population, class := range cases {
+		town := Town{settlement: &settlement{population: population}}
+		if town.GetPopulationClass() != class {
+			t.Errorf("%d people should be a %s, got %s", population, PopulationLookup[class].Name, PopulationLookup[town.GetPopulationClass()].Name)
+		}
+	}
+}
+
+func TestGrowthAndDecline(t *testing.T) {
+	test.NewApp()
+	test.NewWindow(nil)
+	w := world.Init(zap.NewNop().Sugar())
+	ts := Init(w, spatial.New(), zap.NewNop().Sugar())
+	i := 0
+	for len(ts.list[i].pos) < 2 {
+		i++
+	}
+	town := &ts.list[i]
+	copied := ts.GetTowns()[i]
+	seed := town.GetPos()
+
+	// shrunk to its founding tile, there is coast to spread onto again
+	for len(town.pos) > 1 {
+		town.shrink(w)
+	}
+	town.population = PeoplePerTile * 10
+	town.prosperity = MaxProsperity
+	town.prosper(w)
+	if len(town.pos) != 2 || w.GetPositionType(town.pos[1]) != common.TerrainTypeTown {
+		t.Fatalf("crowded town at %v should have spread onto the coast", seed)
+	}
+	if len(copied.GetTiles()) != len(town.pos) {
+		t.Fatalf("copies of the town should see it grow")
+	}
+
+	town.population = AbandonPopulation / 2
+	town.prosperity = -MaxProsperity
+	town.prosper(w)
+	if !copied.IsGhostTown() || w.GetPositionType(seed) != common.TerrainTypeGhostTown || town.fort.GetCannons() != 0 {
+		t.Fatalf("starved town should have been abandoned, its fort left to ruin")
+	}
+	for _, c := range town.pos {
+		if w.GetPositionType(c) != common.TerrainTypeGhostTown {
+			t.Fatalf("ruins at %v should be ghost town", c)
+		}
+	}
+
+	ruins := town.pos
+	town.resettle(w)
+	if copied.IsGhostTown() || w.GetPositionType(seed) != common.TerrainTypeTown || len(town.pos) != 1 {
+		t.Fatalf("resettled town should be lived in again from its founding tile")
+	}
+	for _, c := range ruins[1:] {
+		if w.GetPositionType(c) == common.TerrainTypeGhostTown {
+			t.Fatalf("ruins at %v should have returned to the wild", c)
+		}
+	}
+}
+
+func TestShipyard(t *testing.T) {
+	city := Town{settlement: &settlement{population: PeoplePerTile * 10, flag: common.Flags[4]}}
+	city.stockShipyard()
+	ships := city.GetShipsForSale()
+	if len(ships) != len(ship.ClassLookup) {
+		t.Fatalf("a city should build every class of ship, builds %v", ships)
+	}
+	galleon := ship.ClassType(ship.ClassGalleon)
+	if city.GetShipPrice(galleon) >= ship.ClassLookup[galleon].Price {
+		t.Fatalf("the %s's favourite ship should come cheap in a city", city.GetFlag())
+	}
+	for city.GetShipStock(galleon) > 0 {
+		city.BuyShip(galleon)
+	}
+	if city.BuyShip(galleon) {
+		t.Fatalf("can't buy a ship that's not for sale")
+	}
+
+	hamlet := Town{settlement: &settlement{population: PeoplePerTile / 2, flag: common.Flags[4]}}
+	if ships := hamlet.GetShipsForSale(); len(ships) != 1 || ships[0] != ship.ClassSloop {
+		t.Fatalf("a hamlet should only build sloops, builds %v", ships)
+	}
+	sloop := ship.ClassType(ship.ClassSloop)
+	if hamlet.GetShipPrice(sloop) <= city.GetShipPrice(sloop) {
+		t.Fatalf("ships should be dearer in a hamlet than a city")
+	}
+}
+
+func TestTavernHands(t *testing.T) {
+	city := Town{settlement: &settlement{population: PeoplePerTile * 10}}
+	village := Town{settlement: &settlement{population: PeoplePerTile * 2, prosperity: -10}}
+	city.gatherHands()
+	village.gatherHands()
+	if city.GetHands() <= village.GetHands() {
+		t.Fatalf("a city should have more hands for hire than a village, %d vs %d", city.GetHands(), village.GetHands())
+	}
+	if city.GetHandsMorale() <= village.GetHandsMorale() {
+		t.Fatalf("a struggling village's hands should be in lower spirits")
+	}
+	hands := city.GetHands()
+	if hired := city.HireHands(hands + 10); hired != hands || city.GetHands() != 0 {
+		t.Fatalf("only the %d hands in the tavern can be hired, hired %d", hands, hired)
+	}
+	for i := 0; i < 100; i++ {
+		city.gatherHands()
+	}
+	if city.GetHands() != city.maxHands() {
+		t.Fatalf("the tavern should fill back up to %d hands, has %d", city.maxHands(), city.GetHands())
+	}
+}
+
+func TestDigChannel(t *testing.T) {
+	test.NewApp()
+	test.NewWindow(nil)
+	w := world.Init(zap.NewNop().Sugar())
+	ts := Init(w, spatial.New(), zap.NewNop().Sugar())
+	town := &ts.list[0]
+
+	// land on the coast the town's ships can already reach
+	var dig *common.Coordinates
+	for x := town.GetPos().X - 50; dig == nil && x <= town.GetPos().X+50; x++ {
+		for y := town.GetPos().Y - 50; dig == nil && y <= town.GetPos().Y+50; y++ {
+			c := common.Coordinates{X: x, Y: y}
+			if !common.Inbounds(c) || !w.IsLand(c) {
+				continue
+			}
+			for _, n := range w.GetAdjacentCoords(c) {
+				if w.IsPassableByBoat(n) && town.HeatMap.GetCost(n) >= 0 && town.HeatMap.GetCost(n) < MaxMovementCost {
+					dig = &c
+					break
+				}
+			}
+		}
+	}
+	if dig == nil {
+		t.Fatalf("no coast found near %v", town.GetPos())
+	}
+	before := make([][]HeatMapCost, len(town.HeatMap.grid))
+	for x := range town.HeatMap.grid {
+		before[x] = append([]HeatMapCost{}, town.HeatMap.grid[x]...)
+	}
+	if ts.DigChannel(*dig, w) == 0 || w.GetPositionType(*dig) != common.TerrainTypeShallowWater {
+		t.Fatalf("digging at %v should have opened a channel to %s", *dig, town.GetName())
+	}
+	if town.HeatMap.GetCost(*dig) >= MaxMovementCost {
+		t.Fatalf("the channel should be on the heatmap")
+	}
+	for x := range before {
+		for y := range before[x] {
+			c := common.Coordinates{X: x, Y: y}
+			cost := town.HeatMap.GetCost(c)
+			if cost == before[x][y] {
+				continue
+			}
+			if before[x][y] >= 0 && cost > before[x][y] {
+				t.Fatalf("a channel should only ever shorten the way, %v went from %d to %d", c, before[x][y], cost)
+			}
+			downhill := false
+			for _, n := range w.GetAdjacentCoords(c) {
+				if common.CoordsMatch(n, town.GetPos()) || (w.IsPassableByBoat(n) && town.HeatMap.GetCost(n) >= 0 && town.HeatMap.GetCost(n) < cost) {
+					downhill = true
+				}
+			}
+			if !downhill {
+				t.Fatalf("ships at %v should still find their way downhill to the town", c)
+			}
+		}
+	}
+}
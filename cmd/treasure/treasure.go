@@ -0,0 +1,194 @@
+package treasure
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/town"
+	"pirate-wars/cmd/world"
+
+	"go.uber.org/zap"
+)
+
+// MapChance percentage chance of a tavern round, a wreck or a captain's papers turning up a map
+const MapChance = 15
+
+// MinHoard least gold buried with a treasure
+const MinHoard = 500
+
+// MaxHoard most gold buried with a treasure
+const MaxHoard = 2000
+
+// ChartTiles tiles across the stretch of coast a map shows
+const ChartTiles = 60
+
+// ChartSize pixels across a rendered map
+const ChartSize = 420
+
+// ChartDrift tiles the spot may lie from the middle of the map
+const ChartDrift = ChartTiles / 4
+
+// TownDistance tiles from any town within which nobody would bury a hoard
+const TownDistance = 10
+
+// Treasure a hoard buried on the coast, and the map the player has to find it by
+type Treasure struct {
+	pos    common.Coordinates
+	centre common.Coordinates
+	gold   int
+	source string
+	dug    bool
+	chart  image.Image
+}
+
+type Treasures struct {
+	logger *zap.SugaredLogger
+	world  *world.MapView
+	list   []*Treasure
+}
+
+func (t *Treasure) GetPos() common.Coordinates {
+	return t.pos
+}
+
+// GetSource where the map came from
+func (t *Treasure) GetSource() string {
+	return t.source
+}
+
+func (t *Treasure) IsDug() bool {
+	return t.dug
+}
+
+// Bury hides a hoard on the coast or inland, away from towns, returns it with its map charting the
+// coast as it is when the map is found
+func (ts *Treasures) Bury(source string, towns []town.Town) (*Treasure, bool) {
+	for i := 0; i < 1000; i++ {
+		c := common.RandomPosition()
+		p := ts.world.GetPositionType(c)
+		if p != common.TerrainTypeBeach && p != common.TerrainTypeLowland && p != common.TerrainTypeHighland {
+			continue
+		}
+		if nearTown(c, towns) {
+			continue
+		}
+		t := &Treasure{
+			pos:    c,
+			centre: common.Coordinates{X: c.X + rand.Intn(ChartDrift*2+1) - ChartDrift, Y: c.Y + rand.Intn(ChartDrift*2+1) - ChartDrift},
+			gold:   MinHoard + rand.Intn(MaxHoard-MinHoard+1),
+			source: source,
+		}
+		t.chart = ts.draw(t)
+		ts.list = append(ts.list, t)
+		ts.logger.Infof("Treasure buried at %v, map from %s", c, source)
+		return t, true
+	}
+	return nil, false
+}
+
+// Dig turns over the ground at c, returns the gold of any hoard the player has a map for
+func (ts *Treasures) Dig(c common.Coordinates) (int, bool) {
+	for _, t := range ts.list {
+		if !t.dug && common.CoordsMatch(t.pos, c) {
+			t.dug = true
+			ts.logger.Infof("Treasure dug up at %v: %d gold", c, t.gold)
+			return t.gold, true
+		}
+	}
+	return 0, false
+}
+
+// GetMaps the maps to hoards still in the ground
+func (ts *Treasures) GetMaps() []*Treasure {
+	maps := []*Treasure{}
+	for _, t := range ts.list {
+		if !t.dug {
+			maps = append(maps, t)
+		}
+	}
+	return maps
+}
+
+func nearTown(c common.Coordinates, towns []town.Town) bool {
+	for _, t := range towns {
+		for _, tile := range t.GetTiles() {
+			if common.Distance(c, tile) < TownDistance {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Chart the map to the hoard, drawn when it was found, later changes to the coast aren't on it
+func (t *Treasure) Chart() image.Image {
+	return t.chart
+}
+
+// draw a yellowed and stained close-up of the minimap around the spot, which is marked with a cross
+func (ts *Treasures) draw(t *Treasure) image.Image {
+	img := ts.world.GetChart(t.centre, ChartTiles, ChartSize)
+	age(img, rand.New(rand.NewSource(int64(common.CoordToKey(t.pos)))))
+	px := ChartSize/2 + (t.pos.X-t.centre.X)*ChartSize/ChartTiles
+	py := ChartSize/2 + (t.pos.Y-t.centre.Y)*ChartSize/ChartTiles
+	markSpot(img, px, py)
+	return img
+}
+
+// age turns the chart to faded parchment: sepia toned, blotched, darkened towards the edges and
+// worn ragged around them
+func age(img *image.RGBA, r *rand.Rand) {
+	size := img.Bounds().Dx()
+	type stain struct{ x, y, radius float64 }
+	stains := make([]stain, 3+r.Intn(4))
+	for i := range stains {
+		stains[i] = stain{x: r.Float64() * float64(size), y: r.Float64() * float64(size), radius: float64(size) / float64(8+r.Intn(12))}
+	}
+	mid := float64(size) / 2
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			edge := min(x, y, size-1-x, size-1-y)
+			if edge < 6 && r.Intn(6) >= edge {
+				img.Set(x, y, color.RGBA{0, 0, 0, 0})
+				continue
+			}
+			c := img.RGBAAt(x, y)
+			grey := 0.3*float64(c.R) + 0.59*float64(c.G) + 0.11*float64(c.B)
+			shade := 1 - 0.45*math.Hypot(float64(x)-mid, float64(y)-mid)/(mid*math.Sqrt2)
+			for _, s := range stains {
+				if math.Hypot(float64(x)-s.x, float64(y)-s.y) < s.radius {
+					shade *= 0.85
+				}
+			}
+			shade *= 0.92 + r.Float64()*0.16
+			img.Set(x, y, color.RGBA{
+				R: uint8(min(255, (grey*0.55+110)*shade)),
+				G: uint8(min(255, (grey*0.45+85)*shade)),
+				B: uint8(min(255, (grey*0.3+50)*shade)),
+				A: 255,
+			})
+		}
+	}
+}
+
+// markSpot X marks the spot
+func markSpot(img *image.RGBA, x, y int) {
+	ink := color.RGBA{140, 20, 10, 255}
+	arm := ChartSize / 40
+	for d := -arm; d <= arm; d++ {
+		for w := -1; w <= 1; w++ {
+			img.Set(x+d+w, y+d, ink)
+			img.Set(x+d+w, y-d, ink)
+		}
+	}
+}
+
+func Init(world *world.MapView, logger *zap.SugaredLogger) *Treasures {
+	return &Treasures{
+		logger: logger,
+		world:  world,
+		list:   []*Treasure{},
+	}
+}
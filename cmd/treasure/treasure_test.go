@@ -0,0 +1,47 @@
+package treasure
+
+import (
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/spatial"
+	"pirate-wars/cmd/town"
+	"pirate-wars/cmd/world"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"go.uber.org/zap"
+)
+
+func TestTreasure(t *testing.T) {
+	test.NewApp()
+	test.NewWindow(nil)
+	w := world.Init(zap.NewNop().Sugar())
+	towns := town.Init(w, spatial.New(), zap.NewNop().Sugar()).GetTowns()
+	ts := Init(w, zap.NewNop().Sugar())
+	hoard, ok := ts.Bury("a test", towns)
+	if !ok {
+		t.Fatalf("there should be coast to bury treasure on")
+	}
+	if !w.IsLand(hoard.GetPos()) || !w.IsPassable(hoard.GetPos()) {
+		t.Fatalf("treasure should be buried where a landing party can walk, not at %v", hoard.GetPos())
+	}
+	if nearTown(hoard.GetPos(), towns) {
+		t.Fatalf("treasure shouldn't be buried within %d tiles of a town, buried at %v", TownDistance, hoard.GetPos())
+	}
+	if common.Distance(hoard.GetPos(), hoard.centre) > ChartDrift {
+		t.Fatalf("the spot should be on its map")
+	}
+	chart := hoard.Chart()
+	if chart == nil || chart.Bounds().Dx() != ChartSize || chart != hoard.Chart() {
+		t.Fatalf("the map should be drawn once, when it's found, %d pixels across", ChartSize)
+	}
+	if _, ok := ts.Dig(common.Coordinates{X: hoard.GetPos().X + 1, Y: hoard.GetPos().Y + 1}); ok {
+		t.Fatalf("digging beside the spot shouldn't find anything")
+	}
+	gold, ok := ts.Dig(hoard.GetPos())
+	if !ok || gold < MinHoard || gold > MaxHoard {
+		t.Fatalf("digging at the spot should find the hoard, found %d gold", gold)
+	}
+	if _, ok := ts.Dig(hoard.GetPos()); ok || len(ts.GetMaps()) != 0 {
+		t.Fatalf("a hoard can only be dug up once")
+	}
+}
@@ -9,4 +9,5 @@ const (
 	UserActionIdMiniMap           = 4
 	UserActionIdDebugHeatMap      = 5
 	UserActionIdDebugViewableNpcs = 6
+	UserActionIdBoard             = 7
 )
@@ -0,0 +1,123 @@
+package weather
+
+import (
+	"math/rand"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/ship"
+)
+
+// StormChance percentage chance each day of a new storm brewing
+const StormChance = 40
+
+// MaxStorms storms raging at the same time
+const MaxStorms = 6
+
+// StormDamageChance percentage chance each tick of a ship caught in a storm taking damage
+const StormDamageChance = 10
+
+// WindChangeChance percentage chance each day of the wind veering or backing a point
+const WindChangeChance = 50
+
+type Storm struct {
+	center common.Coordinates
+	radius int
+	days   int
+}
+
+func (s *Storm) GetPos() common.Coordinates {
+	return s.center
+}
+
+func (s *Storm) GetRadius() int {
+	return s.radius
+}
+
+// IsPositionWithin whether c is caught in the storm
+func (s *Storm) IsPositionWithin(c common.Coordinates) bool {
+	return common.Distance(s.center, c) <= s.radius
+}
+
+type Weather struct {
+	storms []*Storm
+	wind   int // index of the compass point the wind blows from
+}
+
+func Init() *Weather {
+	return &Weather{storms: []*Storm{}, wind: rand.Intn(len(common.Compass))}
+}
+
+// GetWind the direction the wind blows towards
+func (w *Weather) GetWind() common.Coordinates {
+	from := common.Compass[w.wind]
+	return common.Coordinates{X: -from.X, Y: -from.Y}
+}
+
+// GetWindName the compass point the wind blows from
+func (w *Weather) GetWindName() string {
+	return common.CompassNames[w.wind]
+}
+
+// IsHeadwind whether sailing in the heading direction means beating against the wind
+func (w *Weather) IsHeadwind(heading common.Coordinates) bool {
+	wind := w.GetWind()
+	return heading.X*wind.X+heading.Y*wind.Y < 0
+}
+
+func (w *Weather) GetStorms() []*Storm {
+	return w.storms
+}
+
+// GetStorm the storm raging at c, or nil when the weather is fair
+func (w *Weather) GetStorm(c common.Coordinates) *Storm {
+	for _, s := range w.storms {
+		if s.IsPositionWithin(c) {
+			return s
+		}
+	}
+	return nil
+}
+
+// NewDay the wind shifts, storms blow themselves out after a few days, and new ones may form
+func (w *Weather) NewDay() {
+	if rand.Intn(100) < WindChangeChance {
+		w.wind = (w.wind + len(common.Compass) + rand.Intn(2)*2 - 1) % len(common.Compass)
+	}
+	active := w.storms[:0]
+	for _, s := range w.storms {
+		s.days--
+		if s.days > 0 {
+			active = append(active, s)
+		}
+	}
+	w.storms = active
+	if len(w.storms) < MaxStorms && rand.Intn(100) < StormChance {
+		w.storms = append(w.storms, &Storm{
+			center: common.RandomPosition(),
+			radius: rand.Intn(8) + 5,
+			days:   rand.Intn(3) + 1,
+		})
+	}
+}
+
+// Tick storms drift across the map
+func (w *Weather) Tick() {
+	for _, s := range w.storms {
+		if rand.Intn(4) == 0 {
+			n := common.AddDirection(s.center, common.Directions[rand.Intn(len(common.Directions))])
+			if common.Inbounds(n) {
+				s.center = n
+			}
+		}
+	}
+}
+
+// Batter a ship caught in a storm may have its sails torn and hull damaged
+func Batter(s *ship.Ship) {
+	if rand.Intn(100) >= StormDamageChance {
+		return
+	}
+	s.DamageSails(rand.Intn(5) + 1)
+	if rand.Intn(3) == 0 {
+		s.Damage(1)
+	}
+}
@@ -49,6 +49,35 @@ func (world *MapView) createRawMapImage(cellWidth, cellHeight float32, cols, row
 	return img
 }
 
+// paintMinimapCell redraws the minimap pixels of a single cell after its terrain has changed
+func (world *MapView) paintMinimapCell(c common.Coordinates) {
+	cellWidth := float32(window.MiniMapArea.Width) / float32(common.WorldCols)
+	cellHeight := float32(window.MiniMapArea.Height) / float32(common.WorldRows)
+	col := terrain.GetColor(world.terrain.Cells[c.X][c.Y])
+	for y := int(float32(c.Y) * cellHeight); y < int(float32(c.Y+1)*cellHeight); y++ {
+		for x := int(float32(c.X) * cellWidth); x < int(float32(c.X+1)*cellWidth); x++ {
+			world.minimap.Set(x, y, col)
+		}
+	}
+}
+
+// GetChart a close-up of the minimap, the given number of tiles across and size pixels square,
+// centred on c. Beyond the edge of the world is open sea.
+func (world *MapView) GetChart(c common.Coordinates, tiles int, size int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			t := common.Coordinates{X: c.X - tiles/2 + x*tiles/size, Y: c.Y - tiles/2 + y*tiles/size}
+			if !common.Inbounds(t) {
+				img.Set(x, y, terrain.GetColor(common.TerrainTypeDeepWater))
+				continue
+			}
+			img.Set(x, y, terrain.GetColor(world.terrain.Cells[t.X][t.Y]))
+		}
+	}
+	return img
+}
+
 func (world *MapView) getMinimapWithOverlays(pos common.Coordinates, entities entities.ViewableEntities) *image.RGBA {
 	cols := common.WorldCols
 	rows := common.WorldRows
@@ -87,16 +116,44 @@ func (world *MapView) getMinimapWithOverlays(pos common.Coordinates, entities en
 	return img
 }
 
+// MinimapListWidth room beside the minimap for the list of places on it
+const MinimapListWidth = 300
+
+// minimapList a line for each entity on the minimap, with its summary if it has one
+func minimapList(list entities.ViewableEntities) *widget.List {
+	return widget.NewList(
+		func() int {
+			return len(list)
+		},
+		func() fyne.CanvasObject {
+			l := widget.NewLabel("")
+			l.Truncation = fyne.TextTruncateEllipsis
+			return container.NewVBox(widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}), l)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			e := list[i]
+			summary := e.GetFlag()
+			if s, ok := e.(entities.SummarisedEntity); ok {
+				summary = s.GetSummary()
+			}
+			rows := o.(*fyne.Container).Objects
+			rows[0].(*widget.Label).SetText(e.GetName())
+			rows[1].(*widget.Label).SetText(fmt.Sprintf("%s (%d, %d)", summary, e.GetPos().X, e.GetPos().Y))
+		},
+	)
+}
+
 func (world *MapView) ShowMinimapPopup(pos common.Coordinates, entities entities.ViewableEntities, w fyne.Window) {
+	minimap := canvas.NewImageFromImage(world.getMinimapWithOverlays(pos, entities))
+	minimap.SetMinSize(fyne.NewSize(float32(window.MiniMapArea.Width), float32(window.MiniMapArea.Height)))
 	minimapPopup = widget.NewModalPopUp(
-		container.NewStack(
-			canvas.NewImageFromImage(world.getMinimapWithOverlays(pos, entities)),
-		),
+		container.NewBorder(nil, nil, minimap, nil, minimapList(entities)),
 		w.Canvas(),
 	)
-	minimapPopup.Resize(fyne.NewSize(float32(window.MiniMapArea.Width), float32(window.MiniMapArea.Height)))
+	width := window.MiniMapArea.Width + MinimapListWidth
+	minimapPopup.Resize(fyne.NewSize(float32(width), float32(window.MiniMapArea.Height)))
 	minimapPopup.Move(
-		fyne.NewPos(float32(window.Window.Width-window.MiniMapArea.Width)/2,
+		fyne.NewPos(float32(window.Window.Width-width)/2,
 			float32(window.Window.Height-window.MiniMapArea.Height)/2),
 	)
 	minimapPopup.Show()
@@ -8,6 +8,7 @@ import (
 	"pirate-wars/cmd/common"
 	"pirate-wars/cmd/entities"
 	"pirate-wars/cmd/resources"
+	"pirate-wars/cmd/spatial"
 	"pirate-wars/cmd/terrain"
 	"pirate-wars/cmd/window"
 
@@ -25,6 +26,18 @@ const ViewTypeMainMap = 0
 const ViewTypeHeatMap = 1
 const ViewTypeMiniMap = 2
 const ViewTypeExamine = 3
+const ViewTypeGameOver = 4
+const ViewTypeBoarding = 5
+const ViewTypeFleet = 6
+const ViewTypePort = 7
+const ViewTypeHideout = 8
+const ViewTypeJournal = 9
+const ViewTypeAshore = 10
+const ViewTypeBindings = 11
+const ViewTypeHelp = 12
+
+// EffectTicks number of paints a visual effect stays on screen
+const EffectTicks = 2
 
 var minimapPopup *widget.PopUp
 
@@ -48,6 +61,14 @@ type MapView struct {
 	viewPort     *fyne.Container
 	minimap      *image.RGBA
 	overlayItems []OverlayItems
+	effects      []effect
+}
+
+// effect a short-lived image drawn over a cell, e.g. cannon fire
+type effect struct {
+	pos   common.Coordinates
+	image image.Image
+	ttl   int
 }
 
 type MinimapOverlay struct {
@@ -61,6 +82,10 @@ type OverlayItems interface {
 	GetTileImage() image.Image
 }
 
+func (world *MapView) AddEffect(c common.Coordinates, e resources.EffectType) {
+	world.effects = append(world.effects, effect{pos: c, image: resources.GetEffectTile(e), ttl: EffectTicks})
+}
+
 func (world *MapView) SetMapItem(m OverlayItems) {
 	world.overlayItems = append(world.overlayItems, m)
 }
@@ -117,8 +142,12 @@ func (world *MapView) GetPositionType(c common.Coordinates) common.TerrainType {
 	return world.terrain.Cells[c.X][c.Y]
 }
 
+// SetPositionType changes the terrain at c, e.g. as towns grow, and repaints it on the minimap
 func (world *MapView) SetPositionType(c common.Coordinates, tt common.TerrainType) {
 	world.terrain.Cells[c.X][c.Y] = tt
+	if world.minimap != nil {
+		world.paintMinimapCell(c)
+	}
 }
 
 func (world *MapView) IsLand(c common.Coordinates) bool {
@@ -157,16 +186,20 @@ func (world *MapView) generateViewPort() {
 	}
 }
 
-func (world *MapView) Paint(avatar entities.AvatarReadOnly, npcs []entities.AvatarReadOnly, highlight entities.ViewableEntity) {
+func (world *MapView) Paint(avatar entities.AvatarReadOnly, index *spatial.Index, highlight entities.ViewableEntity) {
 	p := avatar.GetPos()
 	h := highlight.GetPos()
 	vpr := window.GetViewportRegion(p)
 
-	// Create overlay map
+	// Create overlay map of the forts, NPCs, wrecks and the player's ship and landing party within
+	// the viewport
+	npcs := index.QueryRect(vpr, spatial.KindFort, spatial.KindNpc, spatial.KindWreck, spatial.KindPlayer, spatial.KindParty)
 	overlay := make(map[int]entities.AvatarReadOnly, len(npcs)+2)
 	overlay[common.CoordToKey(p)] = avatar
 	for _, n := range npcs {
-		overlay[common.CoordToKey(n.GetPos())] = n
+		if a, ok := n.(entities.AvatarReadOnly); ok {
+			overlay[common.CoordToKey(n.GetPos())] = a
+		}
 	}
 
 	// if the entity to highlight has real coords, we add it to the overlay
@@ -176,6 +209,18 @@ func (world *MapView) Paint(avatar entities.AvatarReadOnly, npcs []entities.Avat
 		overlay[common.CoordToKey(h)] = highlight
 	}
 
+	// effects are drawn over anything else in the cell, and fade after a few paints
+	effects := make(map[int]image.Image, len(world.effects))
+	active := world.effects[:0]
+	for _, e := range world.effects {
+		effects[common.CoordToKey(e.pos)] = e.image
+		e.ttl--
+		if e.ttl > 0 {
+			active = append(active, e)
+		}
+	}
+	world.effects = active
+
 	vpIdx := 0
 	needsRefresh := false
 
@@ -207,7 +252,9 @@ func (world *MapView) Paint(avatar entities.AvatarReadOnly, npcs []entities.Avat
 		var newTerrainImage image.Image
 		var newEntityImage image.Image
 
-		if item, ok := overlay[common.CoordToKey(pos)]; ok {
+		if img, ok := effects[common.CoordToKey(pos)]; ok {
+			newEntityImage = img
+		} else if item, ok := overlay[common.CoordToKey(pos)]; ok {
 			newEntityImage = item.GetTileImage()
 		} else {
 			newEntityImage = image.NewRGBA(image.Rect(0, 0, window.CellSize, window.CellSize))
@@ -4,7 +4,7 @@ import (
 	"image"
 	"image/color"
 	"pirate-wars/cmd/common"
-	"pirate-wars/cmd/entities"
+	"pirate-wars/cmd/spatial"
 	"pirate-wars/cmd/window"
 	"testing"
 
@@ -70,5 +70,5 @@ func TestPaint(t *testing.T) {
 	avatar := AvatarMock{pos: common.Coordinates{X: 100, Y: 100}, char: '@'}
 	logger := initTestLogger()
 	world := Init(logger)
-	world.Paint(avatar, []entities.AvatarReadOnly{}, avatar)
+	world.Paint(avatar, spatial.New(), avatar)
 }
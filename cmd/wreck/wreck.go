@@ -0,0 +1,159 @@
+package wreck
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/economy"
+	"pirate-wars/cmd/resources"
+	"pirate-wars/cmd/spatial"
+	"pirate-wars/cmd/window"
+
+	"go.uber.org/zap"
+)
+
+// WreckDays days a wreck stays afloat before the sea claims what's left of it
+const WreckDays = 5
+
+// SalvageShare percentage of a sunk ship's cargo and gold that remains in the wreck
+const SalvageShare = 50
+
+type Wreck struct {
+	id        string
+	name      string
+	pos       common.Coordinates
+	shipType  common.ShipType
+	cargo     economy.Goods
+	gold      int
+	days      int
+	blink     bool
+	alternate bool
+}
+
+type Wrecks struct {
+	logger *zap.SugaredLogger
+	index  *spatial.Index
+	list   []*Wreck
+}
+
+func (w *Wreck) GetID() string {
+	return w.id
+}
+
+func (w *Wreck) GetName() string {
+	return w.name
+}
+
+func (w *Wreck) GetType() string {
+	return "Wreck"
+}
+
+func (w *Wreck) GetFlag() string {
+	return ""
+}
+
+func (w *Wreck) GetPos() common.Coordinates {
+	return w.pos
+}
+
+func (w *Wreck) GetPreviousPos() common.Coordinates {
+	return w.pos
+}
+
+func (w *Wreck) GetViewableRange() window.Dimensions {
+	return window.Dimensions{Width: 20, Height: 20}
+}
+
+func (w *Wreck) Highlight(b bool) {
+	w.blink = b
+	w.alternate = b
+}
+
+func (w *Wreck) IsHighlighted() bool {
+	return w.blink
+}
+
+func (w *Wreck) GetColor() color.Color {
+	if w.blink {
+		if !w.alternate {
+			w.alternate = true
+			return color.RGBA{0, 0, 0, 0}
+		}
+	}
+	w.alternate = false
+	return color.RGBA{125, 125, 125, 255}
+}
+
+func (w *Wreck) GetTileImage() image.Image {
+	return resources.GetWreckTile(w.shipType)
+}
+
+func (w *Wreck) GetDetails() string {
+	return fmt.Sprintf("Cargo: %d\nGold: %d\nAfloat for %d days\n", w.cargo.Total(), w.gold, w.days)
+}
+
+// Salvage takes up to capacity cargo and all the gold from the wreck
+func (w *Wreck) Salvage(capacity int) (cargo economy.Goods, gold int) {
+	cargo = w.cargo.Take(capacity)
+	gold = w.gold
+	w.gold = 0
+	return cargo, gold
+}
+
+func (w *Wreck) IsEmpty() bool {
+	return w.cargo.Total() == 0 && w.gold == 0
+}
+
+func Init(index *spatial.Index, logger *zap.SugaredLogger) *Wrecks {
+	return &Wrecks{
+		logger: logger,
+		index:  index,
+		list:   []*Wreck{},
+	}
+}
+
+// Create leaves a wreck where a ship went down, holding part of what it carried
+func (ws *Wrecks) Create(pos common.Coordinates, name string, shipType common.ShipType, hold economy.Goods, gold int) *Wreck {
+	w := &Wreck{
+		id:       common.GenID(pos),
+		name:     name,
+		pos:      pos,
+		shipType: shipType,
+		cargo:    economy.Goods{},
+		gold:     gold * SalvageShare / 100,
+		days:     WreckDays,
+	}
+	for c, n := range hold {
+		w.cargo[c] = n * SalvageShare / 100
+	}
+	ws.logger.Infof("[%v] Wreck created at %v", w.id, pos)
+	ws.list = append(ws.list, w)
+	ws.index.Insert(w, spatial.KindWreck)
+	return w
+}
+
+func (ws *Wrecks) Remove(w *Wreck) {
+	for i, o := range ws.list {
+		if o == w {
+			ws.list = append(ws.list[:i], ws.list[i+1:]...)
+			break
+		}
+	}
+	ws.index.Remove(w.id)
+}
+
+// NewDay wrecks slowly sink out of reach
+func (ws *Wrecks) NewDay() {
+	for _, w := range append([]*Wreck{}, ws.list...) {
+		w.days--
+		if w.days <= 0 {
+			ws.logger.Infof("[%v] Wreck at %v sank out of reach", w.id, w.pos)
+			ws.Remove(w)
+		}
+	}
+}
+
+func (ws *Wrecks) GetList() []*Wreck {
+	return ws.list
+}
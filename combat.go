@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"pirate-wars/cmd/combat"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/faction"
+	"pirate-wars/cmd/npc"
+	"pirate-wars/cmd/resources"
+	"pirate-wars/cmd/ship"
+	"pirate-wars/cmd/spatial"
+	"pirate-wars/cmd/town"
+	"pirate-wars/cmd/weather"
+	"pirate-wars/cmd/wreck"
+)
+
+// fireCannons fires a broadside at the nearest ship within range of the player, or failing that
+// bombards the nearest fort
+func (gs *GameState) fireCannons() {
+	s := gs.player.GetShip()
+	if !s.IsLoaded() {
+		notify("Reloading, the guns aren't ready to fire")
+		return
+	}
+	pos := gs.player.GetPos()
+	// the whole range, the fleet's own ships mustn't crowd out a target behind them
+	for _, item := range spatial.ByDistance(pos, gs.index.QueryRadius(pos, s.GetClass().Range, spatial.KindNpc)) {
+		n := item.(*npc.Npc)
+		d := common.Distance(pos, n.GetPos())
+		if n.IsSinking() || n.IsOwnedByPlayer() || !combat.InRange(s, d) {
+			continue
+		}
+		shot := combat.Fire(s, n.GetShip(), n.GetPos(), d)
+		gs.logger.Infof("Player fires at [%v] %v: %+v", n.GetID(), n.GetPos(), shot)
+		n.TakePlayerFire()
+		gs.relations.AddNotoriety(n.GetFlag(), faction.AttackNotoriety)
+		if n.IsSinking() {
+			gs.towns.ReportPiracy(n.GetPos())
+		}
+		gs.showShot(shot)
+		return
+	}
+	for _, item := range gs.index.Nearest(gs.player.GetPos(), 1, spatial.KindTown) {
+		t := item.(*town.Town)
+		d := common.Distance(gs.player.GetPos(), t.GetPos())
+		if !combat.InRange(s, d) {
+			return
+		}
+		shot := combat.Bombard(s, t.GetFort(), t.GetPos(), d)
+		gs.logger.Infof("Player bombards [%v] %v: %+v", t.GetID(), t.GetPos(), shot)
+		gs.relations.AddNotoriety(t.GetFlag(), faction.AttackNotoriety)
+		gs.towns.ReportPiracy(t.GetPos())
+		gs.showShot(shot)
+	}
+}
+
+// salvage takes what can be carried from an adjacent wreck
+func (gs *GameState) salvage() {
+	pos := gs.player.GetPos()
+	for _, item := range gs.index.QueryRadius(pos, 1, spatial.KindWreck) {
+		w := item.(*wreck.Wreck)
+		s := gs.player.GetShip()
+		cargo, gold := w.Salvage(s.GetClass().Cargo - s.GetCargo())
+		s.LoadGoods(cargo)
+		gs.player.AddGold(gold)
+		notify(fmt.Sprintf("Salvaged %d cargo and %d gold from %s", cargo.Total(), gold, w.GetName()))
+		gs.findMap("the flotsam of " + w.GetName())
+		if w.IsEmpty() {
+			gs.wrecks.Remove(w)
+		}
+		return
+	}
+	notify("No wreck alongside to salvage")
+}
+
+// processWeather ships caught in a storm are battered by wind and waves
+func (gs *GameState) processWeather() {
+	gs.weather.Tick()
+	for _, storm := range gs.weather.GetStorms() {
+		for _, item := range gs.index.QueryRadius(storm.GetPos(), storm.GetRadius(), spatial.KindNpc, spatial.KindPlayer) {
+			if s, ok := item.(interface{ GetShip() *ship.Ship }); ok {
+				weather.Batter(s.GetShip())
+			}
+		}
+	}
+}
+
+// processCombat lets provoked NPCs fire back at the player, and the player's fleet at them
+func (gs *GameState) processCombat() {
+	gs.player.GetShip().Tick()
+	for _, shot := range gs.npcs.ReturnFire(gs.player) {
+		gs.showShot(shot)
+	}
+	for _, shot := range gs.npcs.FleetFire() {
+		gs.showShot(shot)
+	}
+}
+
+// flagship a ship flying a nation's flag, the player's or an NPC's
+type flagship interface {
+	GetFlag() string
+	GetShip() *ship.Ship
+}
+
+// processForts forts fire upon the ships of their nation's enemies, the player's too once wanted
+func (gs *GameState) processForts() {
+	for _, t := range gs.towns.GetTowns() {
+		f := t.GetFort()
+		f.Tick()
+		if !f.IsLoaded() || f.GetCannons() == 0 {
+			continue
+		}
+		for _, item := range spatial.ByDistance(f.GetPos(), gs.index.QueryRadius(f.GetPos(), f.GetRange(), spatial.KindNpc, spatial.KindPlayer)) {
+			target := item.(flagship)
+			if target.GetShip().IsSunk() || !gs.relations.IsHostile(t.GetFlag(), target.GetFlag()) {
+				continue
+			}
+			d := common.Distance(f.GetPos(), item.GetPos())
+			shot := combat.FortFire(f.GetCannons(), f.GetRange(), target.GetShip(), item.GetPos(), d)
+			gs.logger.Infof("Fort [%v] fires at [%v] %v: %+v", f.GetID(), item.GetID(), item.GetPos(), shot)
+			f.Reload()
+			gs.showShot(shot)
+			break
+		}
+	}
+}
+
+func (gs *GameState) showShot(shot combat.Shot) {
+	if shot.IsHit() {
+		gs.world.AddEffect(shot.Target, resources.EffectHit)
+	} else {
+		gs.world.AddEffect(shot.Target, resources.EffectMiss)
+	}
+}
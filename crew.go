@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/ship"
+	"pirate-wars/cmd/world"
+)
+
+// HireCost gold paid to sign on each new hand in port
+const HireCost = 5
+
+var GameOverMessage = "Your ship has sunk!"
+
+// payCrews wages and rations for the flagship and the fleet, if the purse can't cover them all
+// nobody is paid
+func (gs *GameState) payCrews() bool {
+	upkeep := gs.player.GetShip().GetUpkeep()
+	for _, n := range gs.npcs.GetFleet() {
+		upkeep += n.GetShip().GetUpkeep()
+	}
+	if upkeep > gs.player.GetGold() {
+		notify(fmt.Sprintf("Can't pay the crew's %d gold upkeep!", upkeep))
+		return false
+	}
+	gs.player.SpendGold(upkeep)
+	return true
+}
+
+// processCrews the daily change in the crews' spirits, and any mutinies it brings about
+func (gs *GameState) processCrews() {
+	paid := gs.payCrews()
+	s := gs.player.GetShip()
+	s.NewDay(paid, gs.isDocked() || gs.isAtHideoutTavern())
+	if s.GetHungryDays() > ship.StarvationDays {
+		notify("The crew is falling sick from hunger and thirst!")
+	} else if s.GetHungryDays() > 0 {
+		notify("The stores are empty, the crew goes hungry")
+	}
+	for _, n := range gs.npcs.GetFleet() {
+		if n.IsInPort() {
+			// captains lying in port restock at the player's expense
+			gs.restock(n.GetShip())
+		}
+		n.GetShip().NewDay(paid, n.IsInPort())
+		if n.GetShip().IsMutinous() && n.GetShip().Mutiny() {
+			notify(fmt.Sprintf("The crew of %s mutinied and turned on the fleet!", n.GetName()))
+			n.Mutiny(common.PirateFlag)
+		}
+	}
+	if s.IsMutinous() {
+		if s.Mutiny() {
+			gs.logger.Info("Player ship lost to mutiny")
+			GameOverMessage = "Your crew mutinied and cast you adrift!"
+			ViewType = world.ViewTypeGameOver
+			return
+		}
+		notify(fmt.Sprintf("Mutiny put down, the ringleaders hanged. %d crew left", s.GetCrew()))
+	} else if s.GetMorale() < ship.MutinyMorale {
+		notify("The crew is on the brink of mutiny!")
+	}
+}
+
+// cycleRations changes what the crew is given to eat and drink
+func (gs *GameState) cycleRations() {
+	s := gs.player.GetShip()
+	s.SetRations((s.GetRations() + 1) % ship.RationsType(len(ship.RationsLookup)))
+	notify(fmt.Sprintf("Rations: %s", ship.RationsLookup[s.GetRations()].Name))
+}
+
+// hireCrew signs on as many hands as there is room and gold for, at cost gold each
+func (gs *GameState) hireCrew(cost int) {
+	s := gs.player.GetShip()
+	hired := s.AddCrew(gs.player.GetGold() / cost)
+	if hired == 0 {
+		notify("Nobody hired")
+		return
+	}
+	gs.player.SpendGold(hired * cost)
+	notify(fmt.Sprintf("Hired %d crew for %d gold", hired, hired*cost))
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/hideout"
+	"pirate-wars/cmd/npc"
+	"pirate-wars/cmd/town"
+	"pirate-wars/cmd/window"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// fleetRoster selection state of the fleet roster screen
+type fleetRoster struct {
+	selected   int
+	townChoice int
+}
+
+var FleetData = fleetRoster{}
+var fleetPopup *widget.PopUp
+
+func (gs *GameState) selectedFleetShip() *npc.Npc {
+	fleet := gs.npcs.GetFleet()
+	if len(fleet) == 0 {
+		return nil
+	}
+	FleetData.selected = (FleetData.selected + len(fleet)) % len(fleet)
+	return fleet[FleetData.selected]
+}
+
+func (gs *GameState) selectFleetShip(d int) {
+	FleetData.selected += d
+	FleetData.townChoice = 0
+	gs.selectedFleetShip()
+}
+
+func (gs *GameState) orderFollow() {
+	if n := gs.selectedFleetShip(); n != nil {
+		n.Follow(gs.player, gs.npcs.FleetStation(n))
+	}
+}
+
+func (gs *GameState) orderGuard() {
+	if n := gs.selectedFleetShip(); n != nil {
+		n.Guard()
+	}
+}
+
+// orderGoTo sends the selected ship to the nearest town, ordering again picks the next nearest
+func (gs *GameState) orderGoTo() {
+	n := gs.selectedFleetShip()
+	if n == nil {
+		return
+	}
+	sorted := []town.Town{}
+	for _, t := range gs.towns.GetTowns() {
+		// nothing to sail to at the ruins of a town
+		if !t.IsGhostTown() {
+			sorted = append(sorted, t)
+		}
+	}
+	if len(sorted) == 0 {
+		return
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return common.Distance(n.GetPos(), sorted[i].GetPos()) < common.Distance(n.GetPos(), sorted[j].GetPos())
+	})
+	n.GoTo(sorted[FleetData.townChoice%len(sorted)])
+	FleetData.townChoice++
+}
+
+// orderDock sends the selected ship to the nearest hideout, to be repaired at its dock
+func (gs *GameState) orderDock() {
+	n := gs.selectedFleetShip()
+	if n == nil {
+		return
+	}
+	var nearest *hideout.Hideout
+	for _, h := range gs.hideouts.GetList() {
+		if nearest == nil || common.Distance(n.GetPos(), h.GetPos()) < common.Distance(n.GetPos(), nearest.GetPos()) {
+			nearest = h
+		}
+	}
+	if nearest == nil {
+		notify("No hideout to dock at")
+		return
+	}
+	n.Dock(nearest)
+}
+
+func (gs *GameState) fleetRosterContent() *fyne.Container {
+	s := gs.player.GetShip()
+	content := container.NewVBox(
+		widget.NewLabel("Fleet Roster"),
+		widget.NewLabel(fmt.Sprintf("   Flagship - %s - Hull %d/%d - Crew %d - at %v",
+			s.GetClass().Name, s.GetHull(), s.GetClass().Hull, s.GetCrew(), gs.player.GetPos())),
+	)
+	fleet := gs.npcs.GetFleet()
+	if len(fleet) == 0 {
+		content.Add(widget.NewLabel("   No other ships, take prizes to grow your fleet"))
+	}
+	selected := gs.selectedFleetShip()
+	for i, n := range fleet {
+		marker := "  "
+		if n == selected {
+			marker = "> "
+		}
+		fs := n.GetShip()
+		status := n.GetOrders()
+		if n.IsSinking() {
+			status = "Sinking!"
+		}
+		content.Add(widget.NewLabel(fmt.Sprintf("%s%d. %s - %s - Hull %d/%d - Crew %d - %s - at %v",
+			marker, i+1, n.GetName(), fs.GetClass().Name, fs.GetHull(), fs.GetClass().Hull, fs.GetCrew(), status, n.GetPos())))
+	}
+	return content
+}
+
+func (gs *GameState) showFleetPopup(w fyne.Window) {
+	gs.hideFleetPopup()
+	fleetPopup = widget.NewModalPopUp(gs.fleetRosterContent(), w.Canvas())
+	fleetPopup.Resize(fyne.NewSize(float32(window.MiniMapArea.Width), float32(window.MiniMapArea.Height)/2))
+	fleetPopup.Move(
+		fyne.NewPos(float32(window.Window.Width-window.MiniMapArea.Width)/2,
+			float32(window.Window.Height-window.MiniMapArea.Height/2)/2),
+	)
+	fleetPopup.Show()
+}
+
+func (gs *GameState) hideFleetPopup() {
+	if fleetPopup != nil {
+		fleetPopup.Hide()
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"pirate-wars/cmd/user_action"
+	"pirate-wars/cmd/window"
+	"pirate-wars/cmd/world"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// keyCats the categories of keys, in the order the help lists them
+var keyCats = []int{KeyCatNav, KeyCatAction, KeyCatAux, KeyCatAdmin}
+
+var keyCatNames = map[int]string{
+	KeyCatNav:    "Navigation",
+	KeyCatAction: "Actions",
+	KeyCatAux:    "Auxiliary",
+	KeyCatAdmin:  "Admin",
+}
+
+// helpState the view, and the action under way in it, the help was opened from
+type helpState struct {
+	view   int
+	action int
+}
+
+var HelpData = &helpState{}
+
+var helpPopup *widget.PopUp
+
+func openHelp() {
+	HelpData.view, HelpData.action = ViewType, Action
+	Action = user_action.UserActionIdHelp
+	ViewType = world.ViewTypeHelp
+}
+
+func closeHelp() {
+	ViewType, Action = HelpData.view, HelpData.action
+}
+
+// heldHelp how the keys held down move the ship or the landing party, in the views they do
+func heldHelp(view int) (string, bool) {
+	if view != world.ViewTypeMainMap && view != world.ViewTypeAshore {
+		return "", false
+	}
+	held := []string{}
+	for k := range heldDirections {
+		held = append(held, string(k))
+	}
+	sort.Strings(held)
+	return fmt.Sprintf("   %s (held): steer or walk that way, two together for a diagonal",
+		strings.Join(keyLabels(held), ", ")), true
+}
+
+// helpContent the keys of the view the help was opened from, grouped by category
+func helpContent() *fyne.Container {
+	name, km := viewKeyMap(HelpData.view)
+	sections := []fyne.CanvasObject{widget.NewLabel("Help: " + name)}
+	for _, cat := range keyCats {
+		lines := []string{}
+		for _, k := range km {
+			if k.cat == cat {
+				lines = append(lines, fmt.Sprintf("   %s: %s", strings.Join(keyLabels(k.key), ", "), k.help))
+			}
+		}
+		if held, ok := heldHelp(HelpData.view); ok && cat == KeyCatNav {
+			lines = append(lines, held)
+		}
+		if len(lines) > 0 {
+			sections = append(sections, widget.NewLabel(keyCatNames[cat]+":\n"+strings.Join(lines, "\n")))
+		}
+	}
+	return container.NewVBox(sections...)
+}
+
+func showHelpPopup(w fyne.Window) {
+	hideHelpPopup()
+	helpPopup = widget.NewModalPopUp(container.NewVScroll(helpContent()), w.Canvas())
+	helpPopup.Resize(fyne.NewSize(float32(window.MiniMapArea.Width), float32(window.MiniMapArea.Height)))
+	helpPopup.Move(
+		fyne.NewPos(float32(window.Window.Width-window.MiniMapArea.Width)/2,
+			float32(window.Window.Height-window.MiniMapArea.Height)/2),
+	)
+	helpPopup.Show()
+}
+
+func hideHelpPopup() {
+	if helpPopup != nil {
+		helpPopup.Hide()
+	}
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"pirate-wars/cmd/combat"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/economy"
+	"pirate-wars/cmd/hideout"
+	"pirate-wars/cmd/npc"
+	"pirate-wars/cmd/ship"
+	"pirate-wars/cmd/spatial"
+	"pirate-wars/cmd/window"
+	"pirate-wars/cmd/world"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// HideoutHireCost gold paid to sign on each new hand at the hideout's tavern
+const HideoutHireCost = 3
+
+// hideoutState the hideout the player has gone ashore at, and the goods picked in its stash
+type hideoutState struct {
+	hideout  *hideout.Hideout
+	selected int
+}
+
+var HideoutData *hideoutState
+var hideoutPopup *widget.PopUp
+
+// dockedHideout the hideout the player is alongside, if any
+func (gs *GameState) dockedHideout() *hideout.Hideout {
+	pos := gs.player.GetPos()
+	for _, h := range gs.hideouts.GetList() {
+		for _, c := range gs.world.GetAdjacentCoords(pos) {
+			if h.Covers(c) {
+				return h
+			}
+		}
+	}
+	return nil
+}
+
+// foundHideout the crew builds a hideout on the unclaimed coast alongside
+func (gs *GameState) foundHideout() {
+	s := gs.player.GetShip()
+	if gs.player.GetGold() < hideout.FoundingCost || s.GetCrew() <= hideout.FoundingCrew {
+		notify(fmt.Sprintf("A hideout takes %d gold and %d crew to build", hideout.FoundingCost, hideout.FoundingCrew))
+		return
+	}
+	for _, c := range gs.world.GetAdjacentCoords(gs.player.GetPos()) {
+		if !gs.hideouts.CanFound(c, gs.towns.GetTowns()) {
+			continue
+		}
+		gs.player.SpendGold(hideout.FoundingCost)
+		s.LoseCrew(hideout.FoundingCrew)
+		gs.hideouts.Found(c, hideout.FoundingCrew)
+		notify(fmt.Sprintf("Founded a hideout at %v", c))
+		return
+	}
+	notify("No unclaimed coast alongside, keep away from towns and other hideouts")
+}
+
+// enterHideout goes ashore at the hideout alongside
+func (gs *GameState) enterHideout(h *hideout.Hideout) {
+	HideoutData = &hideoutState{hideout: h}
+	ViewType = world.ViewTypeHideout
+}
+
+func leaveHideout() {
+	HideoutData = nil
+	ViewType = world.ViewTypeMainMap
+}
+
+// selectedStashCommodity the commodity picked in the stash
+func selectedStashCommodity() economy.CommodityType {
+	list := economy.Commodities()
+	HideoutData.selected = (HideoutData.selected + len(list)) % len(list)
+	return list[HideoutData.selected]
+}
+
+func selectStashCommodity(d int) {
+	HideoutData.selected += d
+	selectedStashCommodity()
+}
+
+// storeGoods unloads a lot of the selected commodity into the stash
+func (gs *GameState) storeGoods() {
+	c := selectedStashCommodity()
+	s := gs.player.GetShip()
+	stored := s.UnloadCargo(c, HideoutData.hideout.Store(c, min(TradeLot, s.GetHold()[c])))
+	notify(fmt.Sprintf("Stored %d %s", stored, economy.CommodityLookup[c].Name))
+}
+
+// takeGoods loads a lot of the selected commodity from the stash
+func (gs *GameState) takeGoods() {
+	c := selectedStashCommodity()
+	h := HideoutData.hideout
+	s := gs.player.GetShip()
+	taken := s.LoadCargo(c, h.Withdraw(c, min(TradeLot, s.GetClass().Cargo-s.GetCargo())))
+	notify(fmt.Sprintf("Took %d %s", taken, economy.CommodityLookup[c].Name))
+}
+
+// fortifyHideout builds the next level of defences
+func (gs *GameState) fortifyHideout() {
+	h := HideoutData.hideout
+	cost := h.GetDefencesCost()
+	if h.GetDefences() >= hideout.MaxDefences {
+		notify("The defences can't be built any higher")
+		return
+	}
+	if cost > gs.player.GetGold() {
+		notify(fmt.Sprintf("The defences cost %d gold", cost))
+		return
+	}
+	gs.player.SpendGold(cost)
+	gs.hideouts.Fortify(h)
+	notify(fmt.Sprintf("Built defences, %d cannons guard the hideout", h.GetFort().GetCannons()))
+}
+
+func (gs *GameState) buildTavern() {
+	h := HideoutData.hideout
+	if h.HasTavern() {
+		notify("The hideout already has a tavern")
+		return
+	}
+	if hideout.TavernCost > gs.player.GetGold() {
+		notify(fmt.Sprintf("A tavern costs %d gold", hideout.TavernCost))
+		return
+	}
+	gs.player.SpendGold(hideout.TavernCost)
+	h.BuildTavern()
+	notify("Built a tavern, the crew will enjoy their shore leave here")
+}
+
+func (gs *GameState) hireAtHideout() {
+	if !HideoutData.hideout.HasTavern() {
+		notify("Build a tavern to hire crew here")
+		return
+	}
+	gs.hireCrew(HideoutHireCost)
+}
+
+// isAtHideoutTavern whether the player's crew can take shore leave at the hideout alongside
+func (gs *GameState) isAtHideoutTavern() bool {
+	h := gs.dockedHideout()
+	return h != nil && h.HasTavern()
+}
+
+// mostNotorious the nation the player has angered the most, and how much
+func (gs *GameState) mostNotorious() (common.Flag, int) {
+	flag, most := common.Flag{}, 0
+	for _, f := range common.Flags {
+		if n := gs.relations.GetNotoriety(f.Name); n > most {
+			flag, most = f, n
+		}
+	}
+	return flag, most
+}
+
+// processHideouts ships at the docks are repaired, and nations the player has angered enough
+// send pirate-hunters after the hideouts
+func (gs *GameState) processHideouts() {
+	gs.hideouts.NewDay()
+	for _, h := range gs.hideouts.GetList() {
+		if h.InDock(gs.player.GetPos()) {
+			gs.player.GetShip().Repair(hideout.DockRepair)
+		}
+		for _, n := range gs.npcs.GetFleet() {
+			if h.InDock(n.GetPos()) {
+				n.GetShip().Repair(hideout.DockRepair)
+			}
+		}
+		flag, notoriety := gs.mostNotorious()
+		if notoriety < hideout.HunterNotoriety || rand.Intn(100) >= hideout.HunterChance ||
+			len(gs.npcs.GetHunters(h)) >= hideout.MaxHunters {
+			continue
+		}
+		if c, ok := gs.hideouts.HunterSpawn(h); ok {
+			gs.npcs.CreateHunter(c, flag, h)
+			notify(fmt.Sprintf("%s pirate hunters have found your hideout!", flag.Name))
+		}
+	}
+}
+
+// processHideoutBattles the hideouts' batteries fire on hostile ships, and pirate-hunters in range
+// bombard the hideouts, razing any whose garrison falls
+func (gs *GameState) processHideoutBattles() {
+	for _, h := range gs.hideouts.GetList() {
+		f := h.GetFort()
+		f.Tick()
+		// out to whichever reaches further, the battery or the hunters' guns
+		reach := max(f.GetRange(), ship.MaxRange())
+		for _, item := range spatial.ByDistance(h.GetPos(), gs.index.QueryRadius(h.GetPos(), reach, spatial.KindNpc)) {
+			n := item.(*npc.Npc)
+			d := common.Distance(h.GetPos(), n.GetPos())
+			if !n.IsHostile() || n.IsSinking() || n.IsOwnedByPlayer() {
+				continue
+			}
+			if f.IsLoaded() && f.GetCannons() > 0 && d <= f.GetRange() {
+				shot := combat.FortFire(f.GetCannons(), f.GetRange(), n.GetShip(), n.GetPos(), d)
+				f.Reload()
+				gs.showShot(shot)
+			}
+			if n.IsHunter() && n.GetShip().IsLoaded() && combat.InRange(n.GetShip(), d) {
+				shot := combat.Bombard(n.GetShip(), h, h.GetPos(), d)
+				gs.logger.Infof("[%v] Pirate hunter bombards hideout %v: %+v", n.GetID(), h.GetPos(), shot)
+				gs.showShot(shot)
+			}
+		}
+		if h.IsSacked() {
+			gs.hideouts.Remove(h)
+			notify("Your hideout has been sacked, and its stash plundered!")
+			gs.retargetHunters(h)
+			return
+		}
+	}
+}
+
+// retargetHunters the pirate-hunters that sacked the hideout go after the nearest one left, or
+// the player's own ship once there are none
+func (gs *GameState) retargetHunters(sacked *hideout.Hideout) {
+	if h, ok := gs.hideouts.Nearest(sacked.GetPos()); ok {
+		gs.npcs.Retarget(sacked, h)
+		return
+	}
+	if len(gs.npcs.GetHunters(sacked)) > 0 {
+		notify("The pirate hunters turn their guns on your ship!")
+	}
+	gs.npcs.Retarget(sacked, gs.player)
+}
+
+func (gs *GameState) hideoutContent() *fyne.Container {
+	h := HideoutData.hideout
+	s := gs.player.GetShip()
+	lines := []string{fmt.Sprintf("Stash %d/%d (hold %d/%d):", h.GetStash().Total(), hideout.StashCapacity, s.GetCargo(), s.GetClass().Cargo)}
+	selected := selectedStashCommodity()
+	for _, c := range economy.Commodities() {
+		marker := "  "
+		if c == selected {
+			marker = "> "
+		}
+		lines = append(lines, fmt.Sprintf("%s%s: stashed %d - aboard %d", marker, economy.CommodityLookup[c].Name, h.GetStash()[c], s.GetHold()[c]))
+	}
+	lines = append(lines, fmt.Sprintf("Garrison: %d", h.GetGarrison()))
+	if h.GetDefences() < hideout.MaxDefences {
+		lines = append(lines, fmt.Sprintf("Defences: %d/%d - next level %d gold", h.GetDefences(), hideout.MaxDefences, h.GetDefencesCost()))
+	} else {
+		lines = append(lines, fmt.Sprintf("Defences: %d/%d", h.GetDefences(), hideout.MaxDefences))
+	}
+	if h.HasTavern() {
+		lines = append(lines, fmt.Sprintf("Tavern: hands for hire %d gold each - crew %d/%d", HideoutHireCost, s.GetCrew(), s.GetClass().Crew))
+	} else {
+		lines = append(lines, fmt.Sprintf("Tavern: not built - %d gold", hideout.TavernCost))
+	}
+	lines = append(lines, fmt.Sprintf("Dock: ships within %d tiles repaired %d points a day", hideout.DockRange, hideout.DockRepair))
+	return container.NewVBox(
+		widget.NewLabel("Pirate hideout"),
+		widget.NewLabel(fmt.Sprintf("Gold: %d", gs.player.GetGold())),
+		widget.NewLabel(strings.Join(lines, "\n")),
+		widget.NewLabel(strings.Join(Messages, "\n")),
+	)
+}
+
+func (gs *GameState) showHideoutPopup(w fyne.Window) {
+	gs.hideHideoutPopup()
+	hideoutPopup = widget.NewModalPopUp(gs.hideoutContent(), w.Canvas())
+	hideoutPopup.Resize(fyne.NewSize(float32(window.MiniMapArea.Width), float32(window.MiniMapArea.Height)/2))
+	hideoutPopup.Move(
+		fyne.NewPos(float32(window.Window.Width-window.MiniMapArea.Width)/2,
+			float32(window.Window.Height-window.MiniMapArea.Height/2)/2),
+	)
+	hideoutPopup.Show()
+}
+
+func (gs *GameState) hideHideoutPopup() {
+	if hideoutPopup != nil {
+		hideoutPopup.Hide()
+	}
+}
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"pirate-wars/cmd/common"
-	"pirate-wars/cmd/npc"
+	"pirate-wars/cmd/entities"
+	"pirate-wars/cmd/player"
+	"pirate-wars/cmd/spatial"
 	"pirate-wars/cmd/user_action"
+	"pirate-wars/cmd/window"
 	"pirate-wars/cmd/world"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -21,25 +26,103 @@ const KeyCatAction = 3
 const KeyCatAux = 4
 
 type keyItem struct {
-	key  []string
-	cat  int
-	help string
-	exec func(m GameState)
+	// action what the key bindings file calls the action, unlike the help text it never changes
+	action string
+	key    []string
+	cat    int
+	help   string
+	exec   func(m GameState)
 }
 
 type KeyMap []keyItem
 
+// keyNames how keys are shown, where fyne's name for one isn't what's printed on it
+var keyNames = map[string]string{
+	"Left": "←", "Right": "→", "Up": "↑", "Down": "↓", "ctrl+q": "Ctrl+Q", "Return": "Enter", "BackSpace": "Backspace",
+}
+
+func keyName(k string) string {
+	if n, ok := keyNames[k]; ok {
+		return n
+	}
+	return k
+}
+
+// label the help text with the first key bound to it, e.g. "(M) minimap"
+func (k keyItem) label() string {
+	if len(k.key) == 0 {
+		return k.help
+	}
+	return fmt.Sprintf("(%s) %s", keyName(k.key[0]), k.help)
+}
+
 func (m *GameState) handleKeyPress(key *fyne.KeyEvent) {
-	if ViewType == world.ViewTypeMainMap {
-		m.processInput(key, sailingKeyMap)
-	} else if ViewType == world.ViewTypeMiniMap {
-		m.processInput(key, miniMapKeyMap)
-	} else if ViewType == world.ViewTypeExamine {
-		m.processInput(key, examineKeyMap)
+	if ViewType == world.ViewTypeBindings && BindingData.capturing {
+		m.rebind(string(key.Name))
+		return
+	}
+	_, km := viewKeyMap(ViewType)
+	m.processInput(key, km)
+}
+
+// viewKeyMap the name of a view and the keys it answers to
+func viewKeyMap(view int) (string, KeyMap) {
+	if view == world.ViewTypeMainMap {
+		return "Sailing", sailingKeyMap
+	} else if view == world.ViewTypeMiniMap {
+		return "MiniMap", miniMapKeyMap
+	} else if view == world.ViewTypeExamine {
+		return "Examine", examineKeyMap
+	} else if view == world.ViewTypeGameOver {
+		return GameOverMessage, gameOverKeyMap
+	} else if view == world.ViewTypeBoarding {
+		return "Boarded", boardingKeyMap
+	} else if view == world.ViewTypeFleet {
+		return "Fleet", fleetKeyMap
+	} else if view == world.ViewTypePort {
+		if name, ok := portServiceNames[PortData.service]; ok {
+			return name, portKeyMapFor(PortData.service)
+		}
+		return "Port", portKeyMapFor(PortData.service)
+	} else if view == world.ViewTypeHideout {
+		return "Hideout", hideoutKeyMap
+	} else if view == world.ViewTypeJournal {
+		return "Journal", journalKeyMap
+	} else if view == world.ViewTypeAshore {
+		return "Ashore", ashoreKeyMap
+	} else if view == world.ViewTypeBindings {
+		return "Key bindings", bindingsKeyMap
+	} else if view == world.ViewTypeHelp {
+		return "Help", helpKeyMapFor(HelpData.view)
+	}
+	return "", nil
+}
+
+// hints the keys that get no button in the action menu, the navigation and admin keys, e.g.
+// "H/L/K/J navigate  F1 help"
+func (km KeyMap) hints() string {
+	nav := []string{}
+	hints := []string{}
+	for _, k := range km {
+		if len(k.key) == 0 {
+			continue
+		}
+		if k.cat == KeyCatNav {
+			nav = append(nav, keyName(k.key[0]))
+		} else if k.cat == KeyCatAdmin {
+			hints = append(hints, fmt.Sprintf("%s %s", keyName(k.key[0]), k.help))
+		}
+	}
+	if len(nav) > 0 {
+		hints = append([]string{strings.Join(nav, "/") + " navigate"}, hints...)
 	}
+	return strings.Join(hints, "  ")
 }
 
 func (m *GameState) processInput(key *fyne.KeyEvent, km KeyMap) {
+	if steeredByHeldKeys(key.Name) {
+		return
+	}
 	for _, e := range km {
 		for _, k := range e.key {
 			if string(key.Name) == k {
@@ -49,209 +132,462 @@ func (m *GameState) processInput(key *fyne.KeyEvent, km KeyMap) {
 	}
 }
 
+// HeldKeys the keys held down right now, tracked through the desktop canvas' key down and up events
+var HeldKeys = map[fyne.KeyName]bool{}
+
+// heldDirections keys that, held down, steer the ship or walk the landing party, two together give
+// a diagonal
+var heldDirections = map[fyne.KeyName]common.Coordinates{
+	fyne.KeyUp:    {X: 0, Y: -1},
+	fyne.KeyDown:  {X: 0, Y: 1},
+	fyne.KeyLeft:  {X: -1, Y: 0},
+	fyne.KeyRight: {X: 1, Y: 0},
+}
+
+// HeldKeysTracked whether the canvas reports keys going down and up. Only then do held arrow keys
+// steer the ship or walk the party each tick, elsewhere the key maps still answer them a press at
+// a time.
+var HeldKeysTracked = false
+
+// steeredByHeldKeys whether a typed key is left to processHeldKeys, rather than the key maps
+func steeredByHeldKeys(key fyne.KeyName) bool {
+	_, ok := heldDirections[key]
+	return ok && HeldKeysTracked && (ViewType == world.ViewTypeMainMap || ViewType == world.ViewTypeAshore)
+}
+
+func keyDown(key *fyne.KeyEvent) {
+	HeldKeys[key.Name] = true
+}
+
+func keyUp(key *fyne.KeyEvent) {
+	delete(HeldKeys, key.Name)
+}
+
+// heldDirection the direction the keys held down point in, if any
+func heldDirection() (common.Coordinates, bool) {
+	d := common.Coordinates{}
+	for k, v := range heldDirections {
+		if HeldKeys[k] {
+			d = common.AddDirection(d, v)
+		}
+	}
+	return d, d.X != 0 || d.Y != 0
+}
+
+// processHeldKeys each tick a held direction walks the landing party a tile that way, or steers
+// the ship onto it, making sail if she has none set
+func (m *GameState) processHeldKeys() {
+	d, ok := heldDirection()
+	if !ok {
+		return
+	}
+	if m.player.IsAshore() {
+		m.step(d)
+		return
+	}
+	helm := m.player.GetHelm()
+	helm.Steer(common.CompassPoint(d))
+	if helm.GetCanvas() == 0 {
+		helm.Trim(player.MaxCanvas)
+	}
+}
+
 func keyQuit(m GameState) {
 	os.Exit(0)
 }
 
+// sail moves the player's ship to t, if it's navigable. Beating against the wind or sailing
+// through a storm is hard work for the crew.
+func (m *GameState) sail(t common.Coordinates) {
+	if !m.world.IsPassableByBoat(t) {
+		return
+	}
+	c := m.player.GetPos()
+	heading := common.Coordinates{X: t.X - c.X, Y: t.Y - c.Y}
+	m.player.GetShip().Sail(m.weather.IsHeadwind(heading) || m.weather.GetStorm(t) != nil)
+	m.player.SetPos(t)
+	if m.world.GetPositionType(t) == common.TerrainTypeShallowWater {
+		if d := m.player.GetShip().Ground(); d > 0 {
+			notify(fmt.Sprintf("Ran aground! Hull damaged by %d", d))
+		}
+	}
+}
+
+// processHelm the ship makes way on her heading, and is brought up short if there's land ahead
+func (m *GameState) processHelm() {
+	if m.player.IsAshore() {
+		return
+	}
+	helm := m.player.GetHelm()
+	d, ok := helm.Tick(m.player.GetShip(), m.weather.GetWind())
+	if !ok {
+		return
+	}
+	t := common.AddDirection(m.player.GetPos(), d)
+	if !common.Inbounds(t) || !m.world.IsPassableByBoat(t) {
+		helm.Stop()
+		notify("Land ahead, all stop")
+		return
+	}
+	m.sail(t)
+}
+
+// helpKeyItem opens the help overlay for the view the player is in
+var helpKeyItem = keyItem{
+	action: "help",
+	key:    []string{"F1", "/"},
+	cat:    KeyCatAdmin,
+	help:   "help",
+	exec: func(m GameState) {
+		openHelp()
+	},
+}
+
+// helpKeyMapFor the keys of the help overlay opened from view, it closes on Escape or on whichever
+// keys the view's help is bound to
+func helpKeyMapFor(view int) KeyMap {
+	exit := []string{"Escape"}
+	if _, km := viewKeyMap(view); km != nil {
+		if k, ok := km.find(helpKeyItem.action); ok {
+			exit = append(append([]string{}, k.key...), exit...)
+		}
+	}
+	return KeyMap{
+		{
+			key:  exit,
+			help: "exit help",
+			cat:  KeyCatAux,
+			exec: func(m GameState) {
+				closeHelp()
+			},
+		},
+		{
+			key:  []string{"ctrl+q"},
+			help: "quit",
+			cat:  KeyCatAdmin,
+			exec: keyQuit,
+		},
+	}
+}
+
 var miniMapKeyMap = KeyMap{
+	helpKeyItem,
 	{
-		key:  []string{"ctrl+q"},
-		cat:  KeyCatAdmin,
-		help: "(Ctrl+Q) quit",
-		exec: keyQuit,
+		action: "quit",
+		key:    []string{"ctrl+q"},
+		cat:    KeyCatAdmin,
+		help:   "quit",
+		exec:   keyQuit,
 	},
 	{
-		key:  []string{"M", "Enter"},
-		cat:  KeyCatAux,
-		help: "(M) exit minimap",
+		action: "exit",
+		key:    []string{"M", "Enter"},
+		cat:    KeyCatAux,
+		help:   "exit minimap",
 		exec: func(m GameState) {
 			ViewType = world.ViewTypeMainMap
 		},
 	},
 }
 
-var sailingKeyMap = KeyMap{
+var sailingKeyMap = append(KeyMap{
+	helpKeyItem,
 	{
-		key:  []string{"?"},
-		cat:  KeyCatAdmin,
-		help: "(?) Help",
+		action: "bindings",
+		key:    []string{"F2"},
+		cat:    KeyCatAdmin,
+		help:   "key bindings",
 		exec: func(m GameState) {
-			Action = user_action.UserActionIdHelp
+			ViewType = world.ViewTypeBindings
 		},
 	},
 	{
-		key:  []string{"M"},
-		help: "(M) minimap",
-		cat:  KeyCatAux,
+		action: "snapshot",
+		key:    []string{"F5"},
+		cat:    KeyCatAdmin,
+		help:   "write a snapshot of the voyage",
+		exec: func(m GameState) {
+			m.saveGame()
+		},
+	},
+	{
+		action: "minimap",
+		key:    []string{"M"},
+		help:   "minimap",
+		cat:    KeyCatAux,
 		exec: func(m GameState) {
 			ViewType = world.ViewTypeMiniMap
 		},
 	},
 	{
-		key:  []string{"X"},
-		help: "(X) examine",
-		cat:  KeyCatAction,
+		action: "fleet",
+		key:    []string{"O"},
+		help:   "fleet",
+		cat:    KeyCatAux,
+		exec: func(m GameState) {
+			ViewType = world.ViewTypeFleet
+		},
+	},
+	{
+		action: "journal",
+		key:    []string{"I"},
+		help:   "journal",
+		cat:    KeyCatAux,
+		exec: func(m GameState) {
+			ViewType = world.ViewTypeJournal
+		},
+	},
+	{
+		action: "examine",
+		key:    []string{"X"},
+		help:   "examine",
+		cat:    KeyCatAction,
 		exec: func(m GameState) {
 			Action = user_action.UserActionIdExamine
-			npcs := m.npcs.GetVisible(m.player.GetPos(), m.player.GetViewableRange())
+			vpr := window.GetViewportRegion(m.player.GetPos())
+			visible := m.index.QueryRect(vpr, spatial.KindNpc, spatial.KindTown, spatial.KindWreck, spatial.KindHideout)
 			ExamineData = user_action.Examine()
-			if len(npcs.GetList()) > 0 {
+			if len(visible) > 0 {
 				ViewType = world.ViewTypeExamine
-				npcs.ForEach(func(n npc.Npc) {
-					ExamineData.AddItem(&n)
-				})
+				for _, v := range visible {
+					ExamineData.AddItem(v.(entities.ViewableEntity))
+				}
 			}
 		},
 	},
 	{
-		key:  []string{"Left", "H", "A"},
-		help: "left",
-		cat:  KeyCatNav,
+		action: "fire",
+		key:    []string{"F"},
+		help:   "fire cannons",
+		cat:    KeyCatAction,
 		exec: func(m GameState) {
-			c := m.player.GetPos()
-			if c.X > 0 {
-				t := common.Coordinates{
-					X: c.X - 1,
-					Y: c.Y,
-				}
-				if m.world.IsPassableByBoat(t) {
-					m.player.SetPos(t)
-				}
-			}
+			m.fireCannons()
 		},
 	},
 	{
-		key:  []string{"Right", "L", "D"},
-		help: "right",
-		cat:  KeyCatNav,
+		action: "board",
+		key:    []string{"G"},
+		help:   "board ship",
+		cat:    KeyCatAction,
 		exec: func(m GameState) {
-			c := m.player.GetPos()
-			if c.X < m.world.GetWidth()-1 {
-				t := common.Coordinates{
-					X: c.X + 1,
-					Y: c.Y,
-				}
-				if m.world.IsPassableByBoat(t) {
-					m.player.SetPos(t)
-				}
-			}
+			m.boardShip()
 		},
 	},
 	{
-		key:  []string{"Up", "K", "W"},
-		help: "up",
-		cat:  KeyCatNav,
+		action: "salvage",
+		key:    []string{"V"},
+		help:   "salvage wreck",
+		cat:    KeyCatAction,
 		exec: func(m GameState) {
-			c := m.player.GetPos()
-			if c.Y > 0 {
-				t := common.Coordinates{
-					X: c.X,
-					Y: c.Y - 1,
-				}
-				if m.world.IsPassableByBoat(t) {
-					m.player.SetPos(t)
-				}
-			}
+			m.salvage()
 		},
 	},
 	{
-		key:  []string{"Down", "J", "S"},
-		help: "down",
-		cat:  KeyCatNav,
+		action: "enter-town",
+		key:    []string{"T"},
+		help:   "enter town",
+		cat:    KeyCatAction,
 		exec: func(m GameState) {
-			c := m.player.GetPos()
-			if c.Y < m.world.GetHeight()-1 {
-				t := common.Coordinates{
-					X: c.X,
-					Y: c.Y + 1,
-				}
-				if m.world.IsPassableByBoat(t) {
-					m.player.SetPos(t)
-				}
-			}
+			m.enterPort()
 		},
 	},
 	{
-		key:  []string{"Q", "Y"},
-		help: "up & left",
-		cat:  KeyCatNav,
+		action: "raise-hideout",
+		key:    []string{"R"},
+		help:   "raise a hideout",
+		cat:    KeyCatAction,
 		exec: func(m GameState) {
-			c := m.player.GetPos()
-			if c.Y > 0 && c.X > 0 {
-				t := common.Coordinates{
-					X: c.X - 1,
-					Y: c.Y - 1,
-				}
-				if m.world.IsPassableByBoat(t) {
-					m.player.SetPos(t)
-				}
-			}
+			m.foundHideout()
 		},
 	},
 	{
-		key:  []string{"B", "Z"},
-		help: "down & left",
-		cat:  KeyCatNav,
+		action: "rations",
+		key:    []string{"P"},
+		help:   "rations",
+		cat:    KeyCatAction,
 		exec: func(m GameState) {
-			c := m.player.GetPos()
-			if c.Y < m.world.GetHeight()-1 && c.X > 0 {
-				t := common.Coordinates{
-					X: c.X - 1,
-					Y: c.Y + 1,
-				}
-				if m.world.IsPassableByBoat(t) {
-					m.player.SetPos(t)
-				}
-			}
+			m.cycleRations()
 		},
 	},
 	{
-		key:  []string{"U", "E"},
-		help: "up & right",
-		cat:  KeyCatNav,
+		action: "quit",
+		key:    []string{"ctrl+q"},
+		help:   "quit",
+		cat:    KeyCatAdmin,
+		exec:   keyQuit,
+	},
+}, helmKeyMap...)
+
+// helmKeyMap turns the ship's helm and sets or takes in sail
+var helmKeyMap = KeyMap{
+	{
+		action: "port",
+		key:    []string{"Left", "H", "A"},
+		help:   "port",
+		cat:    KeyCatNav,
 		exec: func(m GameState) {
-			c := m.player.GetPos()
-			if c.Y > 0 && c.X < m.world.GetWidth()-1 {
-				t := common.Coordinates{
-					X: c.X + 1,
-					Y: c.Y - 1,
-				}
-				if m.world.IsPassableByBoat(t) {
-					m.player.SetPos(t)
-				}
-			}
+			m.player.GetHelm().Turn(-1)
 		},
 	},
 	{
-		key:  []string{"N", "C"},
-		help: "down & right",
-		cat:  KeyCatNav,
+		action: "starboard",
+		key:    []string{"Right", "L", "D"},
+		help:   "starboard",
+		cat:    KeyCatNav,
 		exec: func(m GameState) {
-			c := m.player.GetPos()
-			if c.Y < m.world.GetHeight()-1 && c.X < m.world.GetWidth()-1 {
-				t := common.Coordinates{
-					X: c.X + 1,
-					Y: c.Y + 1,
-				}
-				if m.world.IsPassableByBoat(t) {
-					m.player.SetPos(t)
-				}
-			}
+			m.player.GetHelm().Turn(1)
 		},
 	},
 	{
-		key:  []string{"ctrl+q"},
-		help: "(Ctrl+Q) quit",
-		cat:  KeyCatAdmin,
-		exec: keyQuit,
+		action: "more-sail",
+		key:    []string{"Up", "K", "W"},
+		help:   "more sail",
+		cat:    KeyCatNav,
+		exec: func(m GameState) {
+			m.player.GetHelm().Trim(1)
+		},
+	},
+	{
+		action: "less-sail",
+		key:    []string{"Down", "J", "S"},
+		help:   "less sail",
+		cat:    KeyCatNav,
+		exec: func(m GameState) {
+			m.player.GetHelm().Trim(-1)
+		},
+	},
+}
+
+var ashoreKeyMap = append(KeyMap{
+	helpKeyItem,
+	{
+		action: "journal",
+		key:    []string{"I"},
+		help:   "journal",
+		cat:    KeyCatAux,
+		exec: func(m GameState) {
+			ViewType = world.ViewTypeJournal
+		},
+	},
+	{
+		action: "dig",
+		key:    []string{"G"},
+		help:   "dig",
+		cat:    KeyCatAction,
+		exec: func(m GameState) {
+			m.dig()
+		},
+	},
+	{
+		action: "dig-channel",
+		key:    []string{"X"},
+		help:   "dig a channel",
+		cat:    KeyCatAction,
+		exec: func(m GameState) {
+			m.digChannel()
+		},
+	},
+	{
+		action: "embark",
+		key:    []string{"T"},
+		help:   "re-embark",
+		cat:    KeyCatAction,
+		exec: func(m GameState) {
+			m.embark()
+		},
+	},
+	{
+		action: "quit",
+		key:    []string{"ctrl+q"},
+		help:   "quit",
+		cat:    KeyCatAdmin,
+		exec:   keyQuit,
+	},
+}, navKeyMap...)
+
+// navKeyMap walks the landing party ashore a tile in each direction
+var navKeyMap = KeyMap{
+	{
+		action: "left",
+		key:    []string{"Left", "H", "A"},
+		help:   "left",
+		cat:    KeyCatNav,
+		exec: func(m GameState) {
+			m.step(common.Coordinates{X: -1, Y: 0})
+		},
+	},
+	{
+		action: "right",
+		key:    []string{"Right", "L", "D"},
+		help:   "right",
+		cat:    KeyCatNav,
+		exec: func(m GameState) {
+			m.step(common.Coordinates{X: 1, Y: 0})
+		},
+	},
+	{
+		action: "up",
+		key:    []string{"Up", "K", "W"},
+		help:   "up",
+		cat:    KeyCatNav,
+		exec: func(m GameState) {
+			m.step(common.Coordinates{X: 0, Y: -1})
+		},
+	},
+	{
+		action: "down",
+		key:    []string{"Down", "J", "S"},
+		help:   "down",
+		cat:    KeyCatNav,
+		exec: func(m GameState) {
+			m.step(common.Coordinates{X: 0, Y: 1})
+		},
+	},
+	{
+		action: "up-left",
+		key:    []string{"Q", "Y"},
+		help:   "up & left",
+		cat:    KeyCatNav,
+		exec: func(m GameState) {
+			m.step(common.Coordinates{X: -1, Y: -1})
+		},
+	},
+	{
+		action: "down-left",
+		key:    []string{"B", "Z"},
+		help:   "down & left",
+		cat:    KeyCatNav,
+		exec: func(m GameState) {
+			m.step(common.Coordinates{X: -1, Y: 1})
+		},
+	},
+	{
+		action: "up-right",
+		key:    []string{"U", "E"},
+		help:   "up & right",
+		cat:    KeyCatNav,
+		exec: func(m GameState) {
+			m.step(common.Coordinates{X: 1, Y: -1})
+		},
+	},
+	{
+		action: "down-right",
+		key:    []string{"N", "C"},
+		help:   "down & right",
+		cat:    KeyCatNav,
+		exec: func(m GameState) {
+			m.step(common.Coordinates{X: 1, Y: 1})
+		},
 	},
 }
 
 var examineKeyMap = KeyMap{
+	helpKeyItem,
 	{
-		key:  []string{"X", "Enter"},
-		help: "(X) exit examine mode",
-		cat:  KeyCatAction,
+		action: "exit",
+		key:    []string{"X", "Enter"},
+		help:   "exit examine mode",
+		cat:    KeyCatAction,
 		exec: func(m GameState) {
 			Action = user_action.UserActionIdNone
 			ViewType = world.ViewTypeMainMap
@@ -259,51 +595,606 @@ var examineKeyMap = KeyMap{
 		},
 	},
 	{
-		key:  []string{"Left", "H", "A"},
-		help: "(←) examine left",
-		cat:  KeyCatAux,
+		action: "left",
+		key:    []string{"Left", "H", "A"},
+		help:   "examine left",
+		cat:    KeyCatAux,
 		exec: func(m GameState) {
 			ExamineData.FocusLeft()
 		},
 	},
 	{
-		key:  []string{"Right", "L", "D"},
-		help: "(→) examine right",
-		cat:  KeyCatAux,
+		action: "right",
+		key:    []string{"Right", "L", "D"},
+		help:   "examine right",
+		cat:    KeyCatAux,
 		exec: func(m GameState) {
 			ExamineData.FocusRight()
 		},
 	},
+	{
+		action: "quit",
+		key:    []string{"ctrl+q"},
+		help:   "quit",
+		cat:    KeyCatAdmin,
+		exec:   keyQuit,
+	},
+}
+
+var gameOverKeyMap = KeyMap{
+	helpKeyItem,
 	{
 		key:  []string{"ctrl+q"},
-		help: "(Ctrl+Q) quit",
+		help: "quit",
 		cat:  KeyCatAdmin,
 		exec: keyQuit,
 	},
 }
 
-func (gs *GameState) ActionItems() *fyne.Container {
-	elements := []fyne.CanvasObject{}
-
-	var keyMap KeyMap
-	if ViewType == world.ViewTypeExamine {
-		elements = append(elements, widget.NewLabel("Examine"))
-		keyMap = examineKeyMap
-	} else if ViewType == world.ViewTypeMiniMap {
-		elements = append(elements, widget.NewLabel("MiniMap"))
-		keyMap = miniMapKeyMap
-	} else if ViewType == world.ViewTypeMainMap {
-		elements = append(elements, widget.NewLabel("Sailing"))
-		keyMap = sailingKeyMap
-	}
+var boardingKeyMap = KeyMap{
+	helpKeyItem,
+	{
+		key:  []string{"1"},
+		help: "plunder cargo",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.plunder()
+		},
+	},
+	{
+		key:  []string{"2"},
+		help: "recruit crew",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.recruit()
+		},
+	},
+	{
+		key:  []string{"3"},
+		help: "take as prize",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.takePrize()
+		},
+	},
+	{
+		key:  []string{"4", "Enter"},
+		help: "let it go",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.letGo()
+		},
+	},
+	{
+		key:  []string{"ctrl+q"},
+		help: "quit",
+		cat:  KeyCatAdmin,
+		exec: keyQuit,
+	},
+}
 
+var fleetKeyMap = KeyMap{
+	helpKeyItem,
+	{
+		key:  []string{"O", "Enter"},
+		help: "exit fleet",
+		cat:  KeyCatAux,
+		exec: func(m GameState) {
+			ViewType = world.ViewTypeMainMap
+		},
+	},
+	{
+		key:  []string{"Up", "K", "W"},
+		help: "previous ship",
+		cat:  KeyCatNav,
+		exec: func(m GameState) {
+			m.selectFleetShip(-1)
+		},
+	},
+	{
+		key:  []string{"Down", "J", "S"},
+		help: "next ship",
+		cat:  KeyCatNav,
+		exec: func(m GameState) {
+			m.selectFleetShip(1)
+		},
+	},
+	{
+		key:  []string{"F"},
+		help: "follow",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.orderFollow()
+		},
+	},
+	{
+		key:  []string{"G"},
+		help: "guard",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.orderGuard()
+		},
+	},
+	{
+		key:  []string{"T"},
+		help: "sail to town",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.orderGoTo()
+		},
+	},
+	{
+		key:  []string{"D"},
+		help: "dock at hideout",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.orderDock()
+		},
+	},
+	{
+		key:  []string{"ctrl+q"},
+		help: "quit",
+		cat:  KeyCatAdmin,
+		exec: keyQuit,
+	},
+}
+
+var portKeyMap = KeyMap{
+	helpKeyItem,
+	{
+		key:  []string{"1"},
+		help: "market",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			visitService(PortServiceMarket)
+		},
+	},
+	{
+		key:  []string{"2"},
+		help: "shipyard",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			visitService(PortServiceShipyard)
+		},
+	},
+	{
+		key:  []string{"3"},
+		help: "tavern",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			visitService(PortServiceTavern)
+		},
+	},
+	{
+		key:  []string{"4"},
+		help: "governor",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			visitService(PortServiceGovernor)
+		},
+	},
+	{
+		key:  []string{"T", "Enter"},
+		help: "leave town",
+		cat:  KeyCatAux,
+		exec: func(m GameState) {
+			leavePort()
+		},
+	},
+	{
+		key:  []string{"ctrl+q"},
+		help: "quit",
+		cat:  KeyCatAdmin,
+		exec: keyQuit,
+	},
+}
+
+// portBackKeys leave a port service for the town's menu
+var portBackKeys = keyItem{
+	key:  []string{"B", "Enter"},
+	help: "back",
+	cat:  KeyCatAux,
+	exec: func(m GameState) {
+		visitService(PortServiceNone)
+	},
+}
+
+var marketKeyMap = KeyMap{
+	helpKeyItem,
+	{
+		key:  []string{"1"},
+		help: "buy supplies",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.buySupplies()
+		},
+	},
+	{
+		key:  []string{"Up", "K", "W"},
+		help: "previous goods",
+		cat:  KeyCatNav,
+		exec: func(m GameState) {
+			selectCommodity(-1)
+		},
+	},
+	{
+		key:  []string{"Down", "J", "S"},
+		help: "next goods",
+		cat:  KeyCatNav,
+		exec: func(m GameState) {
+			selectCommodity(1)
+		},
+	},
+	{
+		key:  []string{"2"},
+		help: "buy goods",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.buyGoods()
+		},
+	},
+	{
+		key:  []string{"3"},
+		help: "sell goods",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.sellGoods()
+		},
+	},
+	portBackKeys,
+	{
+		key:  []string{"ctrl+q"},
+		help: "quit",
+		cat:  KeyCatAdmin,
+		exec: keyQuit,
+	},
+}
+
+var shipyardKeyMap = KeyMap{
+	helpKeyItem,
+	{
+		key:  []string{"1"},
+		help: "repair",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.repairInPort()
+		},
+	},
+	{
+		key:  []string{"Up", "K", "W"},
+		help: "previous ship",
+		cat:  KeyCatNav,
+		exec: func(m GameState) {
+			selectShipClass(-1)
+		},
+	},
+	{
+		key:  []string{"Down", "J", "S"},
+		help: "next ship",
+		cat:  KeyCatNav,
+		exec: func(m GameState) {
+			selectShipClass(1)
+		},
+	},
+	{
+		key:  []string{"2"},
+		help: "buy ship",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.buyShip()
+		},
+	},
+	{
+		key:  []string{"3"},
+		help: "sell prize",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.sellPrize()
+		},
+	},
+	{
+		key:  []string{"Left", "H", "A"},
+		help: "previous refit",
+		cat:  KeyCatNav,
+		exec: func(m GameState) {
+			selectRefit(-1)
+		},
+	},
+	{
+		key:  []string{"Right", "L", "D"},
+		help: "next refit",
+		cat:  KeyCatNav,
+		exec: func(m GameState) {
+			selectRefit(1)
+		},
+	},
+	{
+		key:  []string{"4"},
+		help: "refit",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.refitShip()
+		},
+	},
+	portBackKeys,
+	{
+		key:  []string{"ctrl+q"},
+		help: "quit",
+		cat:  KeyCatAdmin,
+		exec: keyQuit,
+	},
+}
+
+var tavernKeyMap = KeyMap{
+	helpKeyItem,
+	{
+		key:  []string{"1"},
+		help: "hire crew",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.hireHands()
+		},
+	},
+	{
+		key:  []string{"2"},
+		help: "buy a round",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.buyRound()
+		},
+	},
+	portBackKeys,
+	{
+		key:  []string{"ctrl+q"},
+		help: "quit",
+		cat:  KeyCatAdmin,
+		exec: keyQuit,
+	},
+}
+
+var governorKeyMap = KeyMap{
+	helpKeyItem,
+	{
+		key:  []string{"1"},
+		help: "buy a pardon",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.buyPardon()
+		},
+	},
+	{
+		key:  []string{"Up", "K", "W"},
+		help: "previous contract",
+		cat:  KeyCatNav,
+		exec: func(m GameState) {
+			selectOffer(-1)
+		},
+	},
+	{
+		key:  []string{"Down", "J", "S"},
+		help: "next contract",
+		cat:  KeyCatNav,
+		exec: func(m GameState) {
+			selectOffer(1)
+		},
+	},
+	{
+		key:  []string{"2"},
+		help: "accept contract",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.acceptContract()
+		},
+	},
+	portBackKeys,
+	{
+		key:  []string{"ctrl+q"},
+		help: "quit",
+		cat:  KeyCatAdmin,
+		exec: keyQuit,
+	},
+}
+
+var journalKeyMap = KeyMap{
+	helpKeyItem,
+	{
+		key:  []string{"I", "Enter"},
+		help: "exit journal",
+		cat:  KeyCatAux,
+		exec: func(m GameState) {
+			ViewType = m.mapView()
+		},
+	},
+	{
+		key:  []string{"Left", "H", "A"},
+		help: "previous map",
+		cat:  KeyCatNav,
+		exec: func(m GameState) {
+			selectMap(-1)
+		},
+	},
+	{
+		key:  []string{"Right", "L", "D"},
+		help: "next map",
+		cat:  KeyCatNav,
+		exec: func(m GameState) {
+			selectMap(1)
+		},
+	},
+	{
+		key:  []string{"ctrl+q"},
+		help: "quit",
+		cat:  KeyCatAdmin,
+		exec: keyQuit,
+	},
+}
+
+var hideoutKeyMap = KeyMap{
+	helpKeyItem,
+	{
+		key:  []string{"Up", "K", "W"},
+		help: "previous goods",
+		cat:  KeyCatNav,
+		exec: func(m GameState) {
+			selectStashCommodity(-1)
+		},
+	},
+	{
+		key:  []string{"Down", "J", "S"},
+		help: "next goods",
+		cat:  KeyCatNav,
+		exec: func(m GameState) {
+			selectStashCommodity(1)
+		},
+	},
+	{
+		key:  []string{"1"},
+		help: "store goods",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.storeGoods()
+		},
+	},
+	{
+		key:  []string{"2"},
+		help: "take goods",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.takeGoods()
+		},
+	},
+	{
+		key:  []string{"3"},
+		help: "build defences",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.fortifyHideout()
+		},
+	},
+	{
+		key:  []string{"4"},
+		help: "build tavern",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.buildTavern()
+		},
+	},
+	{
+		key:  []string{"5"},
+		help: "hire crew",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.hireAtHideout()
+		},
+	},
+	{
+		key:  []string{"T", "Enter"},
+		help: "leave hideout",
+		cat:  KeyCatAux,
+		exec: func(m GameState) {
+			leaveHideout()
+		},
+	},
+	{
+		key:  []string{"ctrl+q"},
+		help: "quit",
+		cat:  KeyCatAdmin,
+		exec: keyQuit,
+	},
+}
+
+var bindingsKeyMap = KeyMap{
+	{
+		key:  []string{"F2", "Escape"},
+		help: "exit key bindings",
+		cat:  KeyCatAux,
+		exec: func(m GameState) {
+			BindingData.capturing = false
+			ViewType = world.ViewTypeMainMap
+		},
+	},
+	{
+		key:  []string{"Left"},
+		help: "previous key map",
+		cat:  KeyCatAux,
+		exec: func(m GameState) {
+			selectBindableKeyMap(-1)
+		},
+	},
+	{
+		key:  []string{"Right"},
+		help: "next key map",
+		cat:  KeyCatAux,
+		exec: func(m GameState) {
+			selectBindableKeyMap(1)
+		},
+	},
+	{
+		key:  []string{"Up"},
+		help: "previous action",
+		cat:  KeyCatAux,
+		exec: func(m GameState) {
+			selectBinding(-1)
+		},
+	},
+	{
+		key:  []string{"Down"},
+		help: "next action",
+		cat:  KeyCatAux,
+		exec: func(m GameState) {
+			selectBinding(1)
+		},
+	},
+	{
+		key:  []string{"Return", "Enter"},
+		help: "rebind",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			BindingData.capturing = true
+		},
+	},
+	{
+		key:  []string{"BackSpace"},
+		help: "restore default",
+		cat:  KeyCatAction,
+		exec: func(m GameState) {
+			m.resetBinding()
+		},
+	},
+	{
+		key:  []string{"ctrl+q"},
+		help: "quit",
+		cat:  KeyCatAdmin,
+		exec: keyQuit,
+	},
+}
+
+// portKeyMapFor the keys of the town's menu, or of the service being visited
+func portKeyMapFor(service int) KeyMap {
+	switch service {
+	case PortServiceMarket:
+		return marketKeyMap
+	case PortServiceShipyard:
+		return shipyardKeyMap
+	case PortServiceTavern:
+		return tavernKeyMap
+	case PortServiceGovernor:
+		return governorKeyMap
+	}
+	return portKeyMap
+}
+
+func (gs *GameState) ActionItems() *fyne.Container {
+	name, keyMap := viewKeyMap(ViewType)
+	elements := []fyne.CanvasObject{widget.NewLabel(name)}
 	for _, k := range keyMap {
 		if k.cat != KeyCatAdmin && k.cat != KeyCatNav {
-			elements = append(elements, widget.NewButton(k.help, func() {
+			elements = append(elements, widget.NewButton(k.label(), func() {
 				k.exec(*gs)
 			}))
 		}
 	}
+	elements = append(elements, widget.NewLabel(keyMap.hints()))
 
 	return container.NewHBox(elements...)
 }
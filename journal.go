@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"pirate-wars/cmd/contract"
+	"pirate-wars/cmd/treasure"
+	"pirate-wars/cmd/window"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// journalState the treasure map being looked at in the journal
+type journalState struct {
+	selected int
+}
+
+var JournalData = journalState{}
+var journalPopup *widget.PopUp
+
+// selectedMap the treasure map turned to in the journal
+func (gs *GameState) selectedMap() *treasure.Treasure {
+	maps := gs.treasures.GetMaps()
+	if len(maps) == 0 {
+		return nil
+	}
+	JournalData.selected = (JournalData.selected + len(maps)) % len(maps)
+	return maps[JournalData.selected]
+}
+
+func selectMap(d int) {
+	JournalData.selected += d
+}
+
+// offers the contracts the governor of the town the player is visiting has on offer
+func (gs *GameState) offers() []*contract.Contract {
+	return gs.contracts.GetOffers(PortData.town, gs.clock.GetDay())
+}
+
+// selectedOffer the contract picked at the governor's
+func (gs *GameState) selectedOffer() *contract.Contract {
+	list := gs.offers()
+	if len(list) == 0 {
+		return nil
+	}
+	PortData.selected = (PortData.selected + len(list)) % len(list)
+	return list[PortData.selected]
+}
+
+func selectOffer(d int) {
+	PortData.selected += d
+}
+
+// acceptContract signs up for the contract picked at the governor's
+func (gs *GameState) acceptContract() {
+	c := gs.selectedOffer()
+	if c == nil {
+		notify("The governor has no work for you")
+		return
+	}
+	gs.contracts.Accept(c)
+	notify(fmt.Sprintf("Contract accepted: %s", c.GetSummary()))
+}
+
+// processContracts settles the contracts whose conditions have been met, or whose deadline has
+// passed, paying the reward and adjusting the player's standing with the issuing nation
+func (gs *GameState) processContracts() {
+	situation := contract.Situation{
+		Day:    gs.clock.GetDay(),
+		Pos:    gs.player.GetPos(),
+		Docked: gs.dockedTown(),
+		Ship:   gs.player.GetShip(),
+	}
+	for _, c := range gs.contracts.Resolve(situation) {
+		nation := c.GetIssuer().GetFlag()
+		if c.GetStatus() == contract.StatusCompleted {
+			gs.player.AddGold(c.GetReward())
+			gs.relations.Forgive(nation, c.GetReputation())
+			notify(fmt.Sprintf("%s, paid %d gold by %s", c.GetOutcome(), c.GetReward(), c.GetIssuer().GetName()))
+		} else {
+			gs.relations.AddNotoriety(nation, c.GetReputation())
+			notify(fmt.Sprintf("Contract failed: %s", c.GetOutcome()))
+		}
+	}
+}
+
+func (gs *GameState) offersContent() string {
+	lines := []string{"Contracts on offer:"}
+	selected := gs.selectedOffer()
+	if selected == nil {
+		lines = append(lines, "  None")
+	}
+	for _, c := range gs.offers() {
+		marker := "  "
+		if c == selected {
+			marker = "> "
+		}
+		lines = append(lines, fmt.Sprintf("%s%s: %s", marker, contract.TermsLookup[c.GetType()].Name, c.GetDetails()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (gs *GameState) journalContent() *fyne.Container {
+	active := []string{}
+	for _, c := range gs.contracts.GetActive() {
+		active = append(active, fmt.Sprintf("   %s for %s: %s", contract.TermsLookup[c.GetType()].Name, c.GetIssuer().GetName(), c.GetDetails()))
+	}
+	if len(active) == 0 {
+		active = append(active, "   None, governors offer contracts in town")
+	}
+	finished := []string{}
+	for _, c := range gs.contracts.GetFinished() {
+		finished = append(finished, fmt.Sprintf("   %s: %s", contract.StatusLookup[c.GetStatus()], c.GetOutcome()))
+	}
+	if len(finished) == 0 {
+		finished = append(finished, "   None")
+	}
+	contracts := container.NewVBox(
+		widget.NewLabel("Journal"),
+		widget.NewLabel(fmt.Sprintf("Day %d", gs.clock.GetDay())),
+		widget.NewLabel("Active contracts:\n"+strings.Join(active, "\n")),
+		widget.NewLabel("Finished contracts:\n"+strings.Join(finished, "\n")),
+	)
+	return container.NewBorder(nil, nil, nil, gs.treasureMapContent(), contracts)
+}
+
+// treasureMapContent the treasure map turned to, with where it came from
+func (gs *GameState) treasureMapContent() *fyne.Container {
+	maps := gs.treasures.GetMaps()
+	t := gs.selectedMap()
+	if t == nil {
+		return container.NewVBox(widget.NewLabel("Treasure maps: none"))
+	}
+	chart := canvas.NewImageFromImage(t.Chart())
+	chart.SetMinSize(fyne.NewSize(treasure.ChartSize, treasure.ChartSize))
+	return container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("Treasure map %d/%d, from %s", JournalData.selected+1, len(maps), t.GetSource())),
+		chart,
+	)
+}
+
+func (gs *GameState) showJournalPopup(w fyne.Window) {
+	gs.hideJournalPopup()
+	journalPopup = widget.NewModalPopUp(gs.journalContent(), w.Canvas())
+	width := window.MiniMapArea.Width + treasure.ChartSize
+	journalPopup.Resize(fyne.NewSize(float32(width), float32(window.MiniMapArea.Height)))
+	journalPopup.Move(
+		fyne.NewPos(float32(window.Window.Width-width)/2,
+			float32(window.Window.Height-window.MiniMapArea.Height)/2),
+	)
+	journalPopup.Show()
+}
+
+func (gs *GameState) hideJournalPopup() {
+	if journalPopup != nil {
+		journalPopup.Hide()
+	}
+}
@@ -3,19 +3,30 @@ package main
 import (
 	"fmt"
 	"image/color"
+	"pirate-wars/cmd/clock"
 	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/contract"
 	"pirate-wars/cmd/entities"
+	"pirate-wars/cmd/faction"
+	"pirate-wars/cmd/hideout"
 	"pirate-wars/cmd/npc"
 	"pirate-wars/cmd/player"
+	"pirate-wars/cmd/ship"
+	"pirate-wars/cmd/spatial"
 	"pirate-wars/cmd/town"
+	"pirate-wars/cmd/treasure"
+	"pirate-wars/cmd/weather"
 	"pirate-wars/cmd/window"
 	"pirate-wars/cmd/world"
+	"pirate-wars/cmd/wreck"
+	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
@@ -25,18 +36,30 @@ import (
 const BASE_LOG_LEVEL = zap.DebugLevel
 const DEV_MODE = true
 
+// MaxMessages number of recent messages shown in the side panel
+const MaxMessages = 4
+
 var ViewType = world.ViewTypeMainMap
 var SidePanel *fyne.Container
 var ActionMenu *fyne.Container
+var Messages []string
 
 type GameState struct {
 	paused      bool
 	initialized bool
 	logger      *zap.SugaredLogger
 	world       *world.MapView
-	player      *entities.Avatar
+	player      *player.Player
 	npcs        *npc.Npcs
 	towns       *town.Towns
+	index       *spatial.Index
+	wrecks      *wreck.Wrecks
+	hideouts    *hideout.Hideouts
+	contracts   *contract.Contracts
+	treasures   *treasure.Treasures
+	weather     *weather.Weather
+	relations   *faction.Relations
+	clock       clock.Clock
 }
 
 func initGameState(logger *zap.SugaredLogger) *GameState {
@@ -45,22 +68,50 @@ func initGameState(logger *zap.SugaredLogger) *GameState {
 		initialized: false,
 	}
 	gs.logger = logger
+	gs.index = spatial.New()
 	gs.world = world.Init(gs.logger)
-	gs.towns = town.Init(gs.world, gs.logger)
-	gs.npcs = npc.Init(gs.towns, gs.world, gs.logger)
-	gs.player = player.Create(gs.world)
+	gs.towns = town.Init(gs.world, gs.index, gs.logger)
+	gs.wrecks = wreck.Init(gs.index, gs.logger)
+	gs.hideouts = hideout.Init(gs.world, gs.index, gs.logger)
+	gs.treasures = treasure.Init(gs.world, gs.logger)
+	gs.weather = weather.Init()
+	gs.relations = faction.Init()
+	gs.npcs = npc.Init(gs.towns, gs.world, gs.index, gs.wrecks, gs.logger)
+	gs.contracts = contract.Init(gs.towns, gs.npcs, gs.relations, gs.logger)
+	gs.player = player.Create(gs.world, gs.index)
 	return &gs
 }
 
+// notify adds a message to the side panel log
+func notify(msg string) {
+	Messages = append(Messages, msg)
+	if len(Messages) > MaxMessages {
+		Messages = Messages[len(Messages)-MaxMessages:]
+	}
+}
+
 func (gs *GameState) sidePanelContent(examine entities.ViewableEntity) *fyne.Container {
+	s := gs.player.GetShip()
+	helm := gs.player.GetHelm()
 	shipStatusContent := widget.NewLabel(
-		fmt.Sprintf("Galeon\nPostion %+v\nHealth: %d\nSpeed: %d\nCargo: %d\n", gs.player.GetPos(), 100, 5, 250),
+		fmt.Sprintf("%s\nDay %d\nPostion %+v\nHull: %d/%d\nSails: %d%%\nCrew: %d/%d\nMorale: %d (%s)\nRations: %s\nSupplies: %d days (powder %d)\nCannons: %d/%d (range %d)\nHeading: %s (sail %d/%d)\nSpeed: %.1f/%d\nDraft: %d\nCargo: %d/%d\nGold: %d\n%s",
+			s.GetClass().Name, gs.clock.GetDay(), gs.player.GetPos(), s.GetHull(), s.GetClass().Hull, s.GetSails(),
+			s.GetCrew(), s.GetClass().Crew, s.GetMorale(), s.GetMood(), ship.RationsLookup[s.GetRations()].Name,
+			s.GetSupplyDays(), s.GetSupply(ship.SupplyPowder), s.GetGunsManned(), s.GetClass().Cannons, s.GetClass().Range,
+			common.CompassNames[helm.GetHeading()], helm.GetCanvas(), player.MaxCanvas, helm.GetSpeed()*ship.SpeedScale, s.GetClass().Speed, s.GetClass().Draft, s.GetCargo(), s.GetClass().Cargo, gs.player.GetGold(),
+			gs.fleetReport()+gs.weatherReport()+gs.notorietyReport()),
 	)
 	shipStatusContent.Wrapping = fyne.TextWrapWord
-	examineContent := widget.NewLabel(
-		fmt.Sprintf("Captain: %s\nType: %s\nFlag: %s\nPosition: %+v\n",
-			examine.GetName(), examine.GetType(), examine.GetFlag(), examine.GetPos()),
-	)
+	title := "Name"
+	if _, ok := examine.(*npc.Npc); ok {
+		title = "Captain"
+	}
+	examineText := fmt.Sprintf("%s: %s\nType: %s\nFlag: %s\nPosition: %+v\n",
+		title, examine.GetName(), examine.GetType(), examine.GetFlag(), examine.GetPos())
+	if d, ok := examine.(entities.DetailedEntity); ok {
+		examineText += d.GetDetails()
+	}
+	examineContent := widget.NewLabel(examineText)
 	examineContent.Wrapping = fyne.TextWrapWord
 
 	windowContent := widget.NewLabel(fmt.Sprintf("Window: %dx%dpx\nViewport: %dx%dpx\nSide Panel: %dx%dpx\nAction Menu: %dx%dpx\n",
@@ -86,6 +137,10 @@ func (gs *GameState) sidePanelContent(examine entities.ViewableEntity) *fyne.Con
 		canvas.NewRectangle(color.RGBA{R: 200, G: 200, B: 200, A: 255}),
 		examineContent,
 		layout.NewSpacer(),
+		widget.NewLabel("Log"),
+		canvas.NewRectangle(color.RGBA{R: 200, G: 200, B: 200, A: 255}),
+		common.NewWrappedLabel(strings.Join(Messages, "\n")),
+		layout.NewSpacer(),
 		widget.NewLabel("Map Info"),
 		canvas.NewRectangle(color.RGBA{R: 200, G: 200, B: 200, A: 255}),
 		mapContent,
@@ -137,20 +192,78 @@ func (m *GameState) processTick() {
 		return
 	}
 
-	if ViewType == world.ViewTypeMainMap {
-		m.npcs.CalcMovements()
+	if ViewType == world.ViewTypeMainMap || ViewType == world.ViewTypeAshore {
+		if m.clock.Advance() {
+			m.processDay()
+		}
+		m.npcs.CalcMovements(m.player.GetPos())
+		m.processHeldKeys()
+		m.processHelm()
+		m.processWeather()
+		m.processCombat()
+		m.processForts()
+		m.processHideoutBattles()
+		m.processContracts()
+		m.processChannel()
+		if m.player.GetShip().IsSunk() {
+			m.logger.Info("Player ship sunk")
+			ViewType = world.ViewTypeGameOver
+		}
 	}
 
-	// get visible NPCs
 	highlight := ExamineData.GetFocusedEntity()
-	visible := []entities.AvatarReadOnly{}
-	for _, n := range m.npcs.GetList() {
-		visible = append(visible, &n)
-	}
 
 	m.updatePanels(highlight)
 
-	m.world.Paint(m.player, visible, highlight)
+	m.world.Paint(m.focus(), m.index, highlight)
+}
+
+// processDay things that happen once at the start of each day
+func (m *GameState) processDay() {
+	m.logger.Infof("Day %d", m.clock.GetDay())
+	m.weather.NewDay()
+	m.relations.NewDay()
+	for _, n := range m.towns.NewDay(m.world) {
+		notify(n)
+	}
+	m.wrecks.NewDay()
+	m.processHideouts()
+	m.npcs.NewDay()
+	m.contracts.NewDay(m.clock.GetDay())
+	processRumours()
+	if r := m.player.GetShip().RepairAtSea(); r > 0 {
+		notify(fmt.Sprintf("Carpenters repaired %d points", r))
+	}
+	m.processCrews()
+}
+
+func (gs *GameState) fleetReport() string {
+	if n := len(gs.npcs.GetFleet()); n > 0 {
+		return fmt.Sprintf("Fleet: %d ships\n", n)
+	}
+	return ""
+}
+
+// notorietyReport the nations whose forts will fire on the player
+func (gs *GameState) notorietyReport() string {
+	wanted := []string{}
+	for _, f := range common.Flags {
+		if gs.relations.IsWanted(f.Name) {
+			wanted = append(wanted, f.Name)
+		}
+	}
+	if len(wanted) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Wanted by: %s\n", strings.Join(wanted, ", "))
+}
+
+func (gs *GameState) weatherReport() string {
+	report := fmt.Sprintf("Wind: %s\n", gs.weather.GetWindName())
+	if gs.weather.GetStorm(gs.player.GetPos()) != nil {
+		report += "Caught in a storm!\n"
+	}
+	return report
 }
 
 // ⏅ ⏏ ⏚ ⏛ ⏡ ⪮ ⩯ ⩠ ⩟ ⅏
@@ -160,6 +273,7 @@ func main() {
 
 	logger := createLogger()
 	logger.Info("Starting...")
+	loadBindings(logger)
 
 	w := app.NewWindow("Pirate Wars")
 	w.Resize(fyne.NewSize(float32(window.Window.Width), float32(window.Window.Height)))
@@ -208,6 +322,11 @@ func main() {
 
 			go gameState.gameLoop()
 
+			if dc, ok := w.Canvas().(desktop.Canvas); ok {
+				dc.SetOnKeyDown(keyDown)
+				dc.SetOnKeyUp(keyUp)
+				HeldKeysTracked = true
+			}
 			w.Canvas().SetOnTypedKey(func(key *fyne.KeyEvent) {
 				gameState.handleKeyPress(key)
 				if ViewType == world.ViewTypeMiniMap {
@@ -220,6 +339,36 @@ func main() {
 				} else {
 					gameState.world.HideMinimapPopup()
 				}
+				if ViewType == world.ViewTypeFleet {
+					gameState.showFleetPopup(w)
+				} else {
+					gameState.hideFleetPopup()
+				}
+				if ViewType == world.ViewTypePort {
+					gameState.showPortPopup(w)
+				} else {
+					gameState.hidePortPopup()
+				}
+				if ViewType == world.ViewTypeHideout {
+					gameState.showHideoutPopup(w)
+				} else {
+					gameState.hideHideoutPopup()
+				}
+				if ViewType == world.ViewTypeJournal {
+					gameState.showJournalPopup(w)
+				} else {
+					gameState.hideJournalPopup()
+				}
+				if ViewType == world.ViewTypeBindings {
+					showBindingsPopup(w)
+				} else {
+					hideBindingsPopup()
+				}
+				if ViewType == world.ViewTypeHelp {
+					showHelpPopup(w)
+				} else {
+					hideHelpPopup()
+				}
 			})
 		}()
 
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"fmt"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/economy"
+	"pirate-wars/cmd/faction"
+	"pirate-wars/cmd/npc"
+	"pirate-wars/cmd/ship"
+	"pirate-wars/cmd/spatial"
+	"pirate-wars/cmd/town"
+	"pirate-wars/cmd/window"
+	"pirate-wars/cmd/world"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// RepairCost gold a shipwright charges per hull or sail point
+const RepairCost = 2
+
+// PardonCost gold a governor asks for each point of notoriety pardoned
+const PardonCost = 10
+
+// TradeLot units of a commodity bought or sold at a time
+const TradeLot = 10
+
+// ShipyardRange tiles from a town within which the fleet's prizes can be sold at its shipyard
+const ShipyardRange = 6
+
+const (
+	PortServiceNone     = 0
+	PortServiceMarket   = 1
+	PortServiceShipyard = 2
+	PortServiceTavern   = 3
+	PortServiceGovernor = 4
+)
+
+var portServiceNames = map[int]string{
+	PortServiceMarket:   "Market",
+	PortServiceShipyard: "Shipyard",
+	PortServiceTavern:   "Tavern",
+	PortServiceGovernor: "Governor",
+}
+
+// portState the town the player is docked at, and which of its services they're visiting
+type portState struct {
+	town     *town.Town
+	service  int
+	selected int
+	refit    int
+}
+
+var PortData *portState
+var portPopup *widget.PopUp
+
+// dockedTown the town the player is alongside, if any
+func (gs *GameState) dockedTown() *town.Town {
+	pos := gs.player.GetPos()
+	for _, item := range gs.index.QueryRadius(pos, 2, spatial.KindTown) {
+		t := item.(*town.Town)
+		for _, c := range gs.world.GetAdjacentCoords(pos) {
+			if t.Covers(c) {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// isDocked whether the player is alongside a town
+func (gs *GameState) isDocked() bool {
+	return gs.dockedTown() != nil
+}
+
+// enterPort ties up alongside the town and goes ashore
+func (gs *GameState) enterPort() {
+	t := gs.dockedTown()
+	if t == nil {
+		if h := gs.dockedHideout(); h != nil {
+			gs.player.GetHelm().Moor()
+			gs.enterHideout(h)
+			return
+		}
+		if gs.goAshore() {
+			return
+		}
+		notify("No town or coast alongside to go ashore at")
+		return
+	}
+	if t.IsGhostTown() {
+		notify(fmt.Sprintf("%s lies abandoned", t.GetName()))
+		return
+	}
+	gs.player.GetHelm().Moor()
+	PortData = &portState{town: t}
+	ViewType = world.ViewTypePort
+}
+
+func visitService(service int) {
+	PortData.service = service
+	PortData.selected = 0
+	PortData.refit = 0
+}
+
+// selectedCommodity the commodity picked in the market
+func selectedCommodity() economy.CommodityType {
+	list := economy.Commodities()
+	PortData.selected = (PortData.selected + len(list)) % len(list)
+	return list[PortData.selected]
+}
+
+func selectCommodity(d int) {
+	PortData.selected += d
+	selectedCommodity()
+}
+
+// buyGoods buys a lot of the selected commodity, or as much of it as the player can afford and stow
+func (gs *GameState) buyGoods() {
+	c := selectedCommodity()
+	m := PortData.town.GetMarket()
+	s := gs.player.GetShip()
+	price := m.GetPrice(c)
+	units := min(TradeLot, gs.player.GetGold()/price, s.GetClass().Cargo-s.GetCargo())
+	bought := s.LoadCargo(c, m.Buy(c, units))
+	if bought == 0 {
+		notify("Nothing bought")
+		return
+	}
+	gs.player.SpendGold(bought * price)
+	notify(fmt.Sprintf("Bought %d %s for %d gold", bought, economy.CommodityLookup[c].Name, bought*price))
+}
+
+// sellGoods sells a lot of the selected commodity from the hold
+func (gs *GameState) sellGoods() {
+	c := selectedCommodity()
+	m := PortData.town.GetMarket()
+	s := gs.player.GetShip()
+	price := m.GetSellPrice(c)
+	sold := s.UnloadCargo(c, m.Sell(c, min(TradeLot, s.GetHold()[c])))
+	if sold == 0 {
+		notify("Nothing sold")
+		return
+	}
+	gs.player.AddGold(sold * price)
+	notify(fmt.Sprintf("Sold %d %s for %d gold", sold, economy.CommodityLookup[c].Name, sold*price))
+}
+
+// selectedShipClass the class of ship picked at the shipyard
+func selectedShipClass() ship.ClassType {
+	list := PortData.town.GetShipsForSale()
+	PortData.selected = (PortData.selected + len(list)) % len(list)
+	return list[PortData.selected]
+}
+
+func selectShipClass(d int) {
+	PortData.selected += d
+	selectedShipClass()
+}
+
+// selectedRefit the refit picked at the shipyard
+func selectedRefit() ship.RefitType {
+	list := ship.Refits()
+	PortData.refit = (PortData.refit + len(list)) % len(list)
+	return list[PortData.refit]
+}
+
+func selectRefit(d int) {
+	PortData.refit += d
+	selectedRefit()
+}
+
+// buyShip trades the player's ship in for a new one of the selected class, the crew, stores,
+// cargo and strongbox moving aboard
+func (gs *GameState) buyShip() {
+	t := PortData.town
+	c := selectedShipClass()
+	old := gs.player.GetShip()
+	name := ship.ClassLookup[c].Name
+	if t.GetShipStock(c) == 0 {
+		notify(fmt.Sprintf("No %s for sale", name))
+		return
+	}
+	cost := t.GetShipPrice(c) - old.GetValue()
+	if cost > gs.player.GetGold() {
+		notify(fmt.Sprintf("A %s costs %d gold, trading in your %s", name, cost, old.GetClass().Name))
+		return
+	}
+	t.BuyShip(c)
+	s := ship.Create(c)
+	s.Transfer(old)
+	gs.player.SetShip(s)
+	gs.player.SpendGold(cost)
+	if cost >= 0 {
+		notify(fmt.Sprintf("Traded your %s for a %s, paying %d gold", old.GetClass().Name, name, cost))
+	} else {
+		notify(fmt.Sprintf("Traded your %s for a %s, receiving %d gold", old.GetClass().Name, name, -cost))
+	}
+}
+
+// prizesInPort ships of the fleet lying close enough to the town to be sold at its shipyard
+func (gs *GameState) prizesInPort() []*npc.Npc {
+	prizes := []*npc.Npc{}
+	for _, n := range gs.npcs.GetFleet() {
+		if !n.IsSinking() && common.Distance(n.GetPos(), PortData.town.GetPos()) <= ShipyardRange {
+			prizes = append(prizes, n)
+		}
+	}
+	return prizes
+}
+
+// sellPrize sells the first of the fleet's prizes in port, its crew paid off
+func (gs *GameState) sellPrize() {
+	prizes := gs.prizesInPort()
+	if len(prizes) == 0 {
+		notify(fmt.Sprintf("Bring a prize within %d tiles of town to sell it", ShipyardRange))
+		return
+	}
+	n := prizes[0]
+	value := n.GetShip().GetValue()
+	gs.player.AddGold(value)
+	gs.npcs.Remove(n)
+	notify(fmt.Sprintf("Sold %s's %s for %d gold", n.GetName(), n.GetShip().GetClass().Name, value))
+}
+
+// refitShip fits the selected refit to the player's ship
+func (gs *GameState) refitShip() {
+	r := selectedRefit()
+	s := gs.player.GetShip()
+	name := ship.RefitLookup[r].Name
+	if s.HasRefit(r) {
+		notify(fmt.Sprintf("Your ship already has %s", strings.ToLower(name)))
+		return
+	}
+	cost := s.GetRefitPrice(r)
+	if cost > gs.player.GetGold() {
+		notify(fmt.Sprintf("%s costs %d gold", name, cost))
+		return
+	}
+	gs.player.SpendGold(cost)
+	s.AddRefit(r)
+	notify(fmt.Sprintf("Refitted with %s for %d gold", strings.ToLower(name), cost))
+}
+
+func leavePort() {
+	PortData = nil
+	ViewType = world.ViewTypeMainMap
+}
+
+// repairInPort the town's shipwrights fix as much of the damage as the player can pay for
+func (gs *GameState) repairInPort() {
+	s := gs.player.GetShip()
+	points := min(s.GetRepairsNeeded(), gs.player.GetGold()/RepairCost)
+	if points == 0 {
+		notify("Nothing repaired")
+		return
+	}
+	repaired := s.Repair(points)
+	gs.player.SpendGold(repaired * RepairCost)
+	notify(fmt.Sprintf("Repaired %d points for %d gold", repaired, repaired*RepairCost))
+}
+
+// restock fills the ship's stores with as much as the player can pay for, food and water first,
+// returns what was bought
+func (gs *GameState) restock(s *ship.Ship) []string {
+	bought := []string{}
+	for _, t := range []ship.SupplyType{ship.SupplyFood, ship.SupplyWater, ship.SupplyPowder} {
+		supply := ship.SupplyLookup[t]
+		n := s.LoadSupply(t, gs.player.GetGold()/supply.Price)
+		if n > 0 {
+			gs.player.SpendGold(n * supply.Price)
+			bought = append(bought, fmt.Sprintf("%d %s", n, strings.ToLower(supply.Name)))
+		}
+	}
+	return bought
+}
+
+// buySupplies restocks the player's ship in port
+func (gs *GameState) buySupplies() {
+	bought := gs.restock(gs.player.GetShip())
+	if len(bought) == 0 {
+		notify("Nothing bought")
+		return
+	}
+	notify("Bought " + strings.Join(bought, ", "))
+}
+
+// buyPardon the governor clears the player's name with the town's nation, for a price
+func (gs *GameState) buyPardon() {
+	nation := PortData.town.GetFlag()
+	cost := gs.relations.GetNotoriety(nation) * PardonCost
+	if cost == 0 {
+		notify("The governor has nothing to pardon you for")
+		return
+	}
+	if cost > gs.player.GetGold() {
+		notify(fmt.Sprintf("A pardon costs %d gold", cost))
+		return
+	}
+	gs.player.SpendGold(cost)
+	gs.relations.AddNotoriety(nation, -gs.relations.GetNotoriety(nation))
+	notify(fmt.Sprintf("Pardoned by the %s for %d gold", nation, cost))
+}
+
+func (gs *GameState) portServiceContent() string {
+	s := gs.player.GetShip()
+	switch PortData.service {
+	case PortServiceMarket:
+		lines := []string{}
+		for _, t := range []ship.SupplyType{ship.SupplyFood, ship.SupplyWater, ship.SupplyPowder} {
+			supply := ship.SupplyLookup[t]
+			lines = append(lines, fmt.Sprintf("%s: %d gold - aboard %d/%d", supply.Name, supply.Price, s.GetSupply(t), s.GetSupplyCapacity(t)))
+		}
+		lines = append(lines, fmt.Sprintf("Goods (hold %d/%d):", s.GetCargo(), s.GetClass().Cargo))
+		m := PortData.town.GetMarket()
+		selected := selectedCommodity()
+		for _, c := range economy.Commodities() {
+			marker := "  "
+			if c == selected {
+				marker = "> "
+			}
+			lines = append(lines, fmt.Sprintf("%s%s: buy %d, sell %d - stock %d - aboard %d",
+				marker, economy.CommodityLookup[c].Name, m.GetPrice(c), m.GetSellPrice(c), m.GetStock(c), s.GetHold()[c]))
+		}
+		return strings.Join(lines, "\n")
+	case PortServiceShipyard:
+		return gs.shipyardContent()
+	case PortServiceTavern:
+		return gs.tavernContent()
+	case PortServiceGovernor:
+		n := gs.relations.GetNotoriety(PortData.town.GetFlag())
+		if n == 0 {
+			return "The governor welcomes you to town\n" + gs.offersContent()
+		}
+		wanted := ""
+		if n >= faction.WantedNotoriety {
+			wanted = ", you are wanted here"
+		}
+		return fmt.Sprintf("Notoriety: %d%s\nA pardon costs %d gold\n%s", n, wanted, n*PardonCost, gs.offersContent())
+	}
+	return "Where to?"
+}
+
+func (gs *GameState) shipyardContent() string {
+	t := PortData.town
+	s := gs.player.GetShip()
+	lines := []string{
+		fmt.Sprintf("Repairs: %d gold a point - %d points needed", RepairCost, s.GetRepairsNeeded()),
+		fmt.Sprintf("Ships for sale (your %s is worth %d gold in trade):", s.GetClass().Name, s.GetValue()),
+	}
+	selected := selectedShipClass()
+	for _, c := range t.GetShipsForSale() {
+		marker := "  "
+		if c == selected {
+			marker = "> "
+		}
+		class := ship.ClassLookup[c]
+		lines = append(lines, fmt.Sprintf("%s%s: %d gold - %d for sale - %d cannons, hull %d, hold %d, speed %d",
+			marker, class.Name, t.GetShipPrice(c), t.GetShipStock(c), class.Cannons, class.Hull, class.Cargo, class.Speed))
+	}
+	lines = append(lines, "Refits:")
+	refit := selectedRefit()
+	for _, r := range ship.Refits() {
+		marker := "  "
+		if r == refit {
+			marker = "> "
+		}
+		status := fmt.Sprintf("%d gold", s.GetRefitPrice(r))
+		if s.HasRefit(r) {
+			status = "fitted"
+		}
+		lines = append(lines, fmt.Sprintf("%s%s: %s", marker, ship.RefitLookup[r].Name, status))
+	}
+	if prizes := gs.prizesInPort(); len(prizes) > 0 {
+		lines = append(lines, fmt.Sprintf("Prizes in port: %d, the first worth %d gold", len(prizes), prizes[0].GetShip().GetValue()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (gs *GameState) portContent() *fyne.Container {
+	t := PortData.town
+	title := fmt.Sprintf("Port of %s (%s)", t.GetName(), t.GetFlag())
+	if name, ok := portServiceNames[PortData.service]; ok {
+		title += " - " + name
+	}
+	return container.NewVBox(
+		widget.NewLabel(title),
+		widget.NewLabel(fmt.Sprintf("Gold: %d", gs.player.GetGold())),
+		widget.NewLabel(gs.portServiceContent()),
+		widget.NewLabel(strings.Join(Messages, "\n")),
+	)
+}
+
+func (gs *GameState) showPortPopup(w fyne.Window) {
+	gs.hidePortPopup()
+	portPopup = widget.NewModalPopUp(gs.portContent(), w.Canvas())
+	portPopup.Resize(fyne.NewSize(float32(window.MiniMapArea.Width), float32(window.MiniMapArea.Height)))
+	portPopup.Move(
+		fyne.NewPos(float32(window.Window.Width-window.MiniMapArea.Width)/2,
+			float32(window.Window.Height-window.MiniMapArea.Height)/2),
+	)
+	portPopup.Show()
+}
+
+func (gs *GameState) hidePortPopup() {
+	if portPopup != nil {
+		portPopup.Hide()
+	}
+}
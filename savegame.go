@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"pirate-wars/cmd/common"
+	"pirate-wars/cmd/economy"
+	"pirate-wars/cmd/save"
+	"pirate-wars/cmd/town"
+)
+
+// saveGame writes a snapshot of the day, the player, the towns and the hideouts to the save
+// file, there is no loading it back yet
+func (gs *GameState) saveGame() {
+	s := gs.player.GetShip()
+	g := save.Game{
+		Day: gs.clock.GetDay(),
+		Player: save.Player{
+			Pos:   gs.player.GetPos(),
+			Class: s.GetClass().Name,
+			Hull:  s.GetHull(),
+			Crew:  s.GetCrew(),
+			Gold:  gs.player.GetGold(),
+		},
+	}
+	for _, t := range gs.towns.GetTowns() {
+		g.Towns = append(g.Towns, save.Town{
+			Name:       t.GetName(),
+			Nation:     t.GetFlag(),
+			Size:       town.PopulationLookup[t.GetPopulationClass()].Name,
+			Population: t.GetPopulation(),
+			Tiles:      t.GetTiles(),
+		})
+	}
+	for _, h := range gs.hideouts.GetList() {
+		stash := map[string]int{}
+		for c, n := range h.GetStash() {
+			stash[economy.CommodityLookup[c].Name] = n
+		}
+		g.Hideouts = append(g.Hideouts, save.Hideout{
+			Pos:      h.GetPos(),
+			Garrison: h.GetGarrison(),
+			Defences: h.GetDefences(),
+			Tavern:   h.HasTavern(),
+			Stash:    stash,
+		})
+	}
+	if err := save.Write(common.SaveFile, g); err != nil {
+		gs.logger.Errorf("Failed to save game: %v", err)
+		notify("Failed to write the snapshot")
+		return
+	}
+	notify(fmt.Sprintf("Snapshot of day %d written to %s", g.Day, common.SaveFile))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"pirate-wars/cmd/npc"
+	"strings"
+)
+
+// DrinkCost gold for a round of drinks, to loosen tongues in the tavern
+const DrinkCost = 20
+
+// Rumours heard in taverns, and not yet overtaken by events
+var Rumours []*npc.Rumour
+
+// hireHands signs on the town's sailors, as many as there is room and gold for, bringing their
+// spirits aboard with them
+func (gs *GameState) hireHands() {
+	t := PortData.town
+	s := gs.player.GetShip()
+	hired := t.HireHands(s.Recruit(min(t.GetHands(), gs.player.GetGold()/HireCost), t.GetHandsMorale()))
+	if hired == 0 {
+		notify("Nobody hired")
+		return
+	}
+	gs.player.SpendGold(hired * HireCost)
+	notify(fmt.Sprintf("Hired %d crew for %d gold", hired, hired*HireCost))
+}
+
+// buyRound stands the tavern a round of drinks, in return for word of a ship worth chasing
+func (gs *GameState) buyRound() {
+	if DrinkCost > gs.player.GetGold() {
+		notify(fmt.Sprintf("A round of drinks costs %d gold", DrinkCost))
+		return
+	}
+	gs.player.SpendGold(DrinkCost)
+	gs.findMap("an old sailor's keepsakes")
+	r, ok := gs.npcs.Rumour(PortData.town.GetPos(), Rumours)
+	if !ok {
+		notify("The talk is of nothing but the weather")
+		return
+	}
+	Rumours = append(Rumours, r)
+	notify(r.GetText())
+}
+
+// processRumours rumours age, and are forgotten once they're old or no longer true
+func processRumours() {
+	current := []*npc.Rumour{}
+	for _, r := range Rumours {
+		r.Age()
+		if !r.IsStale() {
+			current = append(current, r)
+		}
+	}
+	Rumours = current
+}
+
+func (gs *GameState) tavernContent() string {
+	t := PortData.town
+	s := gs.player.GetShip()
+	lines := []string{
+		fmt.Sprintf("Hands for hire: %d, morale %d - %d gold each - crew %d/%d, morale %d (%s)",
+			t.GetHands(), t.GetHandsMorale(), HireCost, s.GetCrew(), s.GetClass().Crew, s.GetMorale(), s.GetMood()),
+		fmt.Sprintf("A round of drinks: %d gold", DrinkCost),
+	}
+	if len(Rumours) > 0 {
+		lines = append(lines, "Rumours:")
+	}
+	for _, r := range Rumours {
+		lines = append(lines, fmt.Sprintf("  %s (%d days ago)", r.GetText(), r.GetAge()))
+	}
+	return strings.Join(lines, "\n")
+}
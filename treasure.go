@@ -0,0 +1,17 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"pirate-wars/cmd/treasure"
+)
+
+// findMap a treasure map may turn up, from the given source
+func (gs *GameState) findMap(source string) {
+	if rand.Intn(100) >= treasure.MapChance {
+		return
+	}
+	if _, ok := gs.treasures.Bury(source, gs.towns.GetTowns()); ok {
+		notify(fmt.Sprintf("Found a treasure map among %s! See the journal", source))
+	}
+}